@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"math/big"
 
 	"github.com/tempoxyz/tempo-go/examples/feepayer/server"
 	"github.com/tempoxyz/tempo-go/pkg/client"
@@ -24,6 +25,7 @@ func main() {
 	tempoClient := client.New(
 		cfg.TempoRPCURL,
 		client.WithAuth(cfg.TempoUsername, cfg.TempoPassword),
+		client.WithChainID(big.NewInt(int64(cfg.ChainID))),
 	)
 
 	feePayerServer := server.NewFeePayerServer(
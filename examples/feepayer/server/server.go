@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/joho/godotenv"
+	"github.com/tempoxyz/tempo-go/pkg/chains"
 	"github.com/tempoxyz/tempo-go/pkg/client"
 	"github.com/tempoxyz/tempo-go/pkg/signer"
 	"github.com/tempoxyz/tempo-go/pkg/transaction"
@@ -46,7 +47,7 @@ func LoadConfigFromEnv() (*Config, error) {
 		TempoPassword:      getEnv("TEMPO_PASSWORD", ""),
 		FeePayerPrivateKey: getEnv("TEMPO_FEE_PAYER_PRIVATE_KEY", ""),
 		AlphaUSDAddress:    getEnv("ALPHAUSD_ADDRESS", "0x20c0000000000000000000000000000000000001"),
-		ChainID:            getEnvInt("TEMPO_CHAIN_ID", 42424),
+		ChainID:            getEnvInt("TEMPO_CHAIN_ID", int(chains.Testnet.ID)),
 	}
 
 	if err := config.Validate(); err != nil {
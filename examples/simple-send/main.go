@@ -76,35 +76,34 @@ func main() {
 	}
 
 	// Create a new Type 0x76 transaction using the builder pattern
-	tx := transaction.NewBuilder(big.NewInt(chainID)).
+	builder := transaction.NewBuilder(big.NewInt(chainID)).
 		SetNonce(nonce).
 		SetGas(100000).
-		SetMaxFeePerGas(big.NewInt(10000000000)).        // 10 gwei
-		SetMaxPriorityFeePerGas(big.NewInt(1000000000)). // 1 gwei
 		AddCall(
 			common.HexToAddress(recipientAddress),
 			big.NewInt(0),
 			[]byte{}, // Empty data for simple transfer
-		).
-		Build()
+		)
 
-	err = transaction.SignTransaction(tx, sgn)
+	// Fill in fees from recent network conditions
+	quote, err := client.NewFeeOracle(rpcClient, nil).Populate(ctx, builder, client.FeeNormal)
 	if err != nil {
-		log.Fatalf("Failed to sign transaction: %v", err)
+		log.Fatalf("Failed to estimate fees: %v", err)
 	}
+	log.Printf("Max fee per gas: %s, max cost: %s fee token units", quote.MaxFeePerGas, quote.MaxCost)
+
+	tx := builder.Build()
 
-	serialized, err := transaction.Serialize(tx, nil)
+	err = transaction.SignTransaction(tx, sgn)
 	if err != nil {
-		log.Fatalf("Failed to serialize transaction: %v", err)
+		log.Fatalf("Failed to sign transaction: %v", err)
 	}
 
-	log.Printf("Serialized transaction: %s", serialized)
-
 	// Send transaction to the network
-	txHash, err := rpcClient.SendRawTransaction(ctx, serialized)
+	txHash, err := rpcClient.SendTransaction(ctx, tx)
 	if err != nil {
 		log.Fatalf("Failed to send transaction: %v", err)
 	}
 
-	log.Printf("Transaction sent successfully! Hash: %s", txHash)
+	log.Printf("Transaction sent successfully! Hash: %s", txHash.Hex())
 }
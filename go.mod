@@ -5,6 +5,7 @@ go 1.21
 require (
 	github.com/ethereum/go-ethereum v1.13.5
 	github.com/google/go-cmp v0.7.0
+	github.com/gorilla/websocket v1.4.2
 	github.com/stretchr/testify v1.11.1
 )
 
@@ -0,0 +1,137 @@
+package chains
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tempoxyz/tempo-go/pkg/transaction"
+)
+
+// Chain describes a Tempo network. The values returned by this package are shared
+// and must not be modified.
+type Chain struct {
+	// ID is the EIP-155 chain ID reported by eth_chainId.
+	ID uint64
+
+	// Name is the human-readable name of the network.
+	Name string
+
+	// RPCURLs lists the public JSON-RPC endpoints of the network, preferred first.
+	// It is empty when the network has no public endpoint.
+	RPCURLs []string
+
+	// FeeTokens lists TIP-20 stablecoins accepted for fees, the default first.
+	FeeTokens []Token
+
+	// Precompiles holds the addresses of the protocol precompiles.
+	Precompiles Precompiles
+
+	// BlockTime is the target interval between blocks.
+	BlockTime time.Duration
+
+	// Testnet reports whether the network's tokens have no real value.
+	Testnet bool
+}
+
+// Token is a TIP-20 token deployed on a network.
+type Token struct {
+	Symbol  string
+	Address common.Address
+}
+
+// Precompiles holds the addresses of Tempo's protocol precompiles.
+type Precompiles struct {
+	// TIP20Factory creates TIP-20 tokens.
+	TIP20Factory common.Address
+
+	// FeeManager tracks fee token preferences and collects fees.
+	FeeManager common.Address
+
+	// TIP403Registry holds the transfer policies of TIP-20 tokens.
+	TIP403Registry common.Address
+
+	// StablecoinExchange is the enshrined exchange between stablecoins.
+	StablecoinExchange common.Address
+
+	// NonceManager holds the nonces of the 2D nonce keys.
+	NonceManager common.Address
+}
+
+// tempoPrecompiles are the same on every Tempo network.
+var tempoPrecompiles = Precompiles{
+	TIP20Factory:       common.HexToAddress("0x20Fc000000000000000000000000000000000000"),
+	FeeManager:         common.HexToAddress("0xfeEC000000000000000000000000000000000000"),
+	TIP403Registry:     common.HexToAddress("0x403c000000000000000000000000000000000000"),
+	StablecoinExchange: common.HexToAddress("0xdec0000000000000000000000000000000000000"),
+	NonceManager:       common.HexToAddress("0x4E4F4E4345000000000000000000000000000000"),
+}
+
+// pathUSD is the first TIP-20 stablecoin, deployed at genesis on every network.
+var pathUSD = Token{Symbol: "pathUSD", Address: common.HexToAddress("0x20c0000000000000000000000000000000000000")}
+
+var (
+	// Mainnet is the Tempo main network.
+	Mainnet = &Chain{
+		ID:          transaction.ChainIDTempo,
+		Name:        "Tempo",
+		FeeTokens:   []Token{pathUSD},
+		Precompiles: tempoPrecompiles,
+		BlockTime:   500 * time.Millisecond,
+	}
+
+	// Testnet is the public Tempo test network.
+	Testnet = &Chain{
+		ID:      transaction.ChainIDTempoTestnet,
+		Name:    "Tempo Testnet",
+		RPCURLs: []string{"https://rpc.testnet.tempo.xyz"},
+		FeeTokens: []Token{
+			{Symbol: "AlphaUSD", Address: transaction.AlphaUSDAddress},
+			pathUSD,
+			{Symbol: "BetaUSD", Address: common.HexToAddress("0x20c0000000000000000000000000000000000002")},
+			{Symbol: "ThetaUSD", Address: common.HexToAddress("0x20c0000000000000000000000000000000000003")},
+		},
+		Precompiles: tempoPrecompiles,
+		BlockTime:   500 * time.Millisecond,
+		Testnet:     true,
+	}
+)
+
+// All returns every known network, mainnet first.
+func All() []*Chain {
+	return []*Chain{Mainnet, Testnet}
+}
+
+// ByID returns the network with the given chain ID.
+func ByID(id uint64) (*Chain, bool) {
+	for _, chain := range All() {
+		if chain.ID == id {
+			return chain, true
+		}
+	}
+	return nil, false
+}
+
+// ChainID returns the chain ID as a *big.Int, as used by transactions.
+func (c *Chain) ChainID() *big.Int {
+	return new(big.Int).SetUint64(c.ID)
+}
+
+// DefaultFeeToken returns the address of the first fee token, or the zero address
+// if the network lists none.
+func (c *Chain) DefaultFeeToken() common.Address {
+	if len(c.FeeTokens) == 0 {
+		return common.Address{}
+	}
+	return c.FeeTokens[0].Address
+}
+
+// FeeToken returns the fee token with the given symbol.
+func (c *Chain) FeeToken(symbol string) (Token, bool) {
+	for _, token := range c.FeeTokens {
+		if token.Symbol == symbol {
+			return token, true
+		}
+	}
+	return Token{}, false
+}
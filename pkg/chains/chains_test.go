@@ -0,0 +1,69 @@
+package chains
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempoxyz/tempo-go/pkg/transaction"
+)
+
+func TestByID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   uint64
+		want *Chain
+	}{
+		{"mainnet", 42424, Mainnet},
+		{"testnet", 42429, Testnet},
+		{"unknown", 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain, ok := ByID(tt.id)
+			assert.Equal(t, tt.want != nil, ok)
+			assert.Same(t, tt.want, chain)
+		})
+	}
+}
+
+func TestChains(t *testing.T) {
+	ids := make(map[uint64]bool)
+	for _, chain := range All() {
+		t.Run(chain.Name, func(t *testing.T) {
+			assert.False(t, ids[chain.ID], "duplicate chain ID")
+			ids[chain.ID] = true
+
+			assert.NotEmpty(t, chain.FeeTokens)
+			assert.Positive(t, chain.BlockTime)
+			assert.NotEqual(t, common.Address{}, chain.Precompiles.NonceManager)
+			assert.Equal(t, new(big.Int).SetUint64(chain.ID), chain.ChainID())
+		})
+	}
+}
+
+func TestTestnet(t *testing.T) {
+	assert.Equal(t, uint64(transaction.ChainIDTempoTestnet), Testnet.ID)
+	assert.True(t, Testnet.Testnet)
+	assert.Equal(t, []string{"https://rpc.testnet.tempo.xyz"}, Testnet.RPCURLs)
+	assert.Equal(t, transaction.AlphaUSDAddress, Testnet.DefaultFeeToken())
+
+	token, ok := Testnet.FeeToken("pathUSD")
+	require.True(t, ok)
+	assert.Equal(t, common.HexToAddress("0x20c0000000000000000000000000000000000000"), token.Address)
+
+	_, ok = Testnet.FeeToken("USDX")
+	assert.False(t, ok)
+}
+
+func TestChainIDIsACopy(t *testing.T) {
+	Testnet.ChainID().SetInt64(1)
+	assert.Equal(t, uint64(transaction.ChainIDTempoTestnet), Testnet.ChainID().Uint64())
+}
+
+func TestDefaultFeeTokenWithoutTokens(t *testing.T) {
+	assert.Equal(t, common.Address{}, (&Chain{}).DefaultFeeToken())
+}
@@ -0,0 +1,19 @@
+// Package chains describes the Tempo networks: their chain IDs, default RPC
+// endpoints, fee tokens, precompile addresses and block times.
+//
+// # Basic Usage
+//
+//	chain := chains.Testnet
+//	rpc := client.New(chain.RPCURLs[0], client.WithChain(chain))
+//
+//	tx := transaction.NewBuilder(chain.ChainID()).
+//		SetFeeToken(chain.DefaultFeeToken()).
+//		...
+//
+// Look up a network by the chain ID found in configuration:
+//
+//	chain, ok := chains.ByID(cfg.ChainID)
+//	if !ok {
+//		log.Fatalf("unknown chain ID %d", cfg.ChainID)
+//	}
+package chains
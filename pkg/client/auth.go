@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TokenSource supplies the bearer token sent with every request. Token is called
+// for each HTTP request and WebSocket handshake, so implementations can rotate or
+// refresh credentials; they must be safe for concurrent use.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc adapts a function to the TokenSource interface.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// Token calls f.
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// WithHeader adds an HTTP header to every request and WebSocket handshake.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		if c.header == nil {
+			c.header = make(http.Header)
+		}
+		c.header.Add(key, value)
+	}
+}
+
+// WithBearerToken authenticates every request with a static bearer token.
+func WithBearerToken(token string) Option {
+	return WithTokenSource(TokenSourceFunc(func(context.Context) (string, error) {
+		return token, nil
+	}))
+}
+
+// WithTokenSource authenticates every request with a bearer token from source. The
+// token replaces basic auth credentials and any Authorization header set with
+// WithHeader.
+func WithTokenSource(source TokenSource) Option {
+	return func(c *Client) {
+		c.tokens = source
+	}
+}
+
+// WithJWTAuth authenticates every request with an HS256 JWT signed with secret, as
+// used by the engine API. See NewJWTTokenSource.
+func WithJWTAuth(secret []byte) Option {
+	return WithTokenSource(NewJWTTokenSource(secret))
+}
+
+// NewJWTTokenSource returns a token source that issues an HS256 JWT signed with
+// secret for every request. The tokens carry an iat claim with the current time,
+// which nodes check against their own clock.
+func NewJWTTokenSource(secret []byte) TokenSource {
+	key := append([]byte(nil), secret...)
+	return TokenSourceFunc(func(context.Context) (string, error) {
+		return signJWT(key, time.Now()), nil
+	})
+}
+
+// jwtHeader is the encoded header of every token issued by NewJWTTokenSource.
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// signJWT creates an HS256 JWT with an iat claim of now.
+func signJWT(secret []byte, now time.Time) string {
+	claims := base64.RawURLEncoding.EncodeToString([]byte(`{"iat":` + strconv.FormatInt(now.Unix(), 10) + `}`))
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(jwtHeader + "." + claims))
+	return jwtHeader + "." + claims + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// ParseJWTSecret parses a hex-encoded 32 byte JWT secret, in the format of the
+// jwt.hex files shared between nodes. Surrounding whitespace and a 0x prefix are
+// ignored.
+func ParseJWTSecret(s string) ([]byte, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "0x")
+	secret, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT secret: %w", err)
+	}
+	if len(secret) != 32 {
+		return nil, fmt.Errorf("invalid JWT secret: expected 32 bytes, got %d", len(secret))
+	}
+	return secret, nil
+}
+
+// authHeader returns the headers sent with every request authenticated with the given
+// basic auth credentials: the headers set with WithHeader, then basic auth, then the
+// bearer token of the token source.
+func (c *Client) authHeader(ctx context.Context, username, password string) (http.Header, error) {
+	header := c.header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	if username != "" || password != "" {
+		req := &http.Request{Header: header}
+		req.SetBasicAuth(username, password)
+	}
+	if c.tokens != nil {
+		token, err := c.tokens.Token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get auth token: %w", err)
+		}
+		header.Set("Authorization", "Bearer "+token)
+	}
+	return header, nil
+}
+
+// splitCredentials removes the user info from rawURL and returns it as basic auth
+// credentials, so that they are sent in a header and not kept in the URL.
+func splitCredentials(rawURL string) (cleanURL, username, password string) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil || u.Host == "" {
+		return rawURL, "", ""
+	}
+	username = u.User.Username()
+	password, _ = u.User.Password()
+	u.User = nil
+	return u.String(), username, password
+}
+
+// minKeySegmentLen is the length from which redactURL treats a path segment as an
+// API key. Provider keys are 32 characters or more; path names are far shorter.
+const minKeySegmentLen = 20
+
+// redactURL returns rawURL with its credentials removed, for error messages, logs and
+// endpoint statuses. User info is dropped and query values, which often carry API
+// keys, are masked. So are path segments of minKeySegmentLen characters or more,
+// which is how providers embed API keys, as in /v3/<key>.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "invalid URL"
+	}
+	u.User = nil
+	if u.Host != "" {
+		segments := strings.Split(u.Path, "/")
+		for i, segment := range segments {
+			if len(segment) >= minKeySegmentLen {
+				segments[i] = "redacted"
+			}
+		}
+		u.Path = strings.Join(segments, "/")
+		u.RawPath = ""
+	}
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			query[key] = []string{"redacted"}
+		}
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+// redactError replaces the URL of a *url.Error in err, as returned by http.Client,
+// with its redacted form.
+func redactError(err error, rawURL string) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redactURL(rawURL)
+	}
+	return err
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// BatchOptions configures how SendBatch splits large batches into chunks.
+// Zero values are replaced with defaults.
+type BatchOptions struct {
+	// MaxItems is the largest number of requests sent in one chunk. Defaults to 100.
+	MaxItems int
+
+	// MaxBytes is the largest encoded size of a chunk. A request larger than MaxBytes
+	// is sent in a chunk of its own. Defaults to 1 MiB.
+	MaxBytes int
+
+	// Concurrency is the number of chunks sent at the same time. Defaults to 4.
+	Concurrency int
+}
+
+// defaultBatchOptions returns the chunking used when WithBatchOptions is not set.
+func defaultBatchOptions() *BatchOptions {
+	return &BatchOptions{MaxItems: 100, MaxBytes: 1 << 20, Concurrency: 4}
+}
+
+// WithBatchOptions sets how batches are split into chunks, to stay within the batch
+// limits of the node or provider.
+func WithBatchOptions(opts *BatchOptions) Option {
+	o := *defaultBatchOptions()
+	if opts != nil {
+		if opts.MaxItems > 0 {
+			o.MaxItems = opts.MaxItems
+		}
+		if opts.MaxBytes > 0 {
+			o.MaxBytes = opts.MaxBytes
+		}
+		if opts.Concurrency > 0 {
+			o.Concurrency = opts.Concurrency
+		}
+	}
+	return func(c *Client) {
+		c.batchOpts = &o
+	}
+}
+
+// BatchItemError is the error of a single request of a batch. It unwraps to the
+// JSON-RPC error of the request, ErrNotFound for a null result, a decoding error, or
+// the error of the chunk the request was sent in, so it can be classified with
+// errors.Is, errors.As or Classify.
+type BatchItemError struct {
+	// Index is the position of the request in the batch.
+	Index int
+
+	// Method is the method of the request.
+	Method string
+
+	Err error
+}
+
+// Error implements the error interface for BatchItemError.
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("batch item %d (%s): %v", e.Index, e.Method, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// batchChunk is a slice of the requests of a batch and the outcome of sending it.
+type batchChunk struct {
+	offset    int
+	requests  []*JSONRPCRequest
+	responses []*JSONRPCResponse
+	err       error
+}
+
+// chunkBatch splits requests into chunks within the client's batch limits.
+func (c *Client) chunkBatch(requests []*JSONRPCRequest) ([]*batchChunk, error) {
+	opts := c.batchOpts
+	if opts == nil {
+		opts = defaultBatchOptions()
+	}
+
+	var chunks []*batchChunk
+	current := &batchChunk{}
+	size := 0
+	for i, request := range requests {
+		encoded, err := json.Marshal(request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal batch request: %w", err)
+		}
+		// Each request adds its encoding and a separator to the enclosing array.
+		n := len(encoded) + 1
+		if len(current.requests) > 0 && (len(current.requests) >= opts.MaxItems || size+n+1 > opts.MaxBytes) {
+			chunks = append(chunks, current)
+			current = &batchChunk{offset: i}
+			size = 0
+		}
+		current.requests = append(current.requests, request)
+		size += n
+	}
+	return append(chunks, current), nil
+}
+
+// sendChunks sends the chunks of a batch, at most Concurrency at a time, and records
+// the responses or error of each chunk.
+func (c *Client) sendChunks(ctx context.Context, chunks []*batchChunk) {
+	if len(chunks) == 1 {
+		chunks[0].responses, chunks[0].err = c.invoke(ctx, &Invocation{Requests: chunks[0].requests, Batch: true})
+		return
+	}
+
+	concurrency := defaultBatchOptions().Concurrency
+	if c.batchOpts != nil {
+		concurrency = c.batchOpts.Concurrency
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, chunk := range chunks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(chunk *batchChunk) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			chunk.responses, chunk.err = c.invoke(ctx, &Invocation{Requests: chunk.requests, Batch: true})
+		}(chunk)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,210 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chunkServer answers batches with each request's first param, and records the size
+// of every batch it receives. Batches containing a request for "fail_chunk" get an
+// HTTP 400.
+type chunkServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	sizes    []int
+	inFlight atomic.Int32
+	maxSeen  atomic.Int32
+}
+
+func newChunkServer(t *testing.T) *chunkServer {
+	s := &chunkServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := s.inFlight.Add(1)
+		defer s.inFlight.Add(-1)
+		for {
+			seen := s.maxSeen.Load()
+			if n <= seen || s.maxSeen.CompareAndSwap(seen, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		var reqs []JSONRPCRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqs))
+		s.mu.Lock()
+		s.sizes = append(s.sizes, len(reqs))
+		s.mu.Unlock()
+
+		responses := make([]*JSONRPCResponse, len(reqs))
+		for i, req := range reqs {
+			switch req.Method {
+			case "fail_chunk":
+				http.Error(w, "bad chunk", http.StatusBadRequest)
+				return
+			case "reject":
+				responses[i] = NewJSONRPCErrorResponse(req.ID, -32000, "nonce too low", nil)
+			default:
+				responses[i] = NewJSONRPCResponse(req.ID, req.Params[0])
+			}
+		}
+		json.NewEncoder(w).Encode(responses)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *chunkServer) batchSizes() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sizes := append([]int(nil), s.sizes...)
+	for i := 1; i < len(sizes); i++ {
+		for j := i; j > 0 && sizes[j] > sizes[j-1]; j-- {
+			sizes[j], sizes[j-1] = sizes[j-1], sizes[j]
+		}
+	}
+	return sizes
+}
+
+func TestBatchChunking(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("by item count", func(t *testing.T) {
+		server := newChunkServer(t)
+		c := New(server.URL, WithBatchOptions(&BatchOptions{MaxItems: 4, Concurrency: 2}))
+
+		batch := NewBatchRequest()
+		for i := 0; i < 10; i++ {
+			batch.Add("echo", fmt.Sprint(i))
+		}
+		responses, err := c.SendBatch(ctx, batch)
+		require.NoError(t, err)
+		require.Len(t, responses, 10)
+		for i, response := range responses {
+			assert.Equal(t, fmt.Sprint(i), response.Result)
+			assert.Equal(t, i+1, response.ID)
+		}
+		assert.Equal(t, []int{4, 4, 2}, server.batchSizes())
+		assert.LessOrEqual(t, server.maxSeen.Load(), int32(2))
+	})
+
+	t.Run("by size", func(t *testing.T) {
+		server := newChunkServer(t)
+		c := New(server.URL, WithBatchOptions(&BatchOptions{MaxBytes: 300}))
+
+		large := strings.Repeat("a", 300)
+		batch := NewBatchRequest().
+			Add("echo", strings.Repeat("b", 80)).
+			Add("echo", strings.Repeat("c", 80)).
+			Add("echo", large).
+			Add("echo", "d")
+		responses, err := c.SendBatch(ctx, batch)
+		require.NoError(t, err)
+		require.Len(t, responses, 4)
+		assert.Equal(t, large, responses[2].Result)
+		// The oversized request is sent on its own.
+		assert.Equal(t, []int{2, 1, 1}, server.batchSizes())
+	})
+
+	t.Run("default limits", func(t *testing.T) {
+		server := newChunkServer(t)
+		batch := NewBatchRequest()
+		for i := 0; i < 250; i++ {
+			batch.Add("echo", "x")
+		}
+		responses, err := New(server.URL).SendBatch(ctx, batch)
+		require.NoError(t, err)
+		assert.Len(t, responses, 250)
+		assert.Equal(t, []int{100, 100, 50}, server.batchSizes())
+	})
+
+	t.Run("failed chunk", func(t *testing.T) {
+		server := newChunkServer(t)
+		c := New(server.URL, WithBatchOptions(&BatchOptions{MaxItems: 2}))
+
+		batch := NewBatchRequest().Add("echo", "0").Add("echo", "1").Add("fail_chunk", "2").Add("echo", "3").Add("echo", "4")
+		responses, err := c.SendBatch(ctx, batch)
+		var httpErr *HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusBadRequest, httpErr.StatusCode)
+		assert.Contains(t, err.Error(), "batch items 2 to 3")
+
+		// The responses of the chunks that were sent are kept.
+		require.Len(t, responses, 5)
+		for _, i := range []int{0, 1, 4} {
+			require.NotNil(t, responses[i])
+			assert.Equal(t, fmt.Sprint(i), responses[i].Result)
+		}
+		assert.Nil(t, responses[2])
+		assert.Nil(t, responses[3])
+	})
+
+	t.Run("every chunk failed", func(t *testing.T) {
+		server := newChunkServer(t)
+		c := New(server.URL, WithBatchOptions(&BatchOptions{MaxItems: 1}))
+
+		responses, err := c.SendBatch(ctx, NewBatchRequest().Add("fail_chunk", "0").Add("fail_chunk", "1"))
+		assert.Error(t, err)
+		assert.Nil(t, responses)
+	})
+
+	t.Run("batch rejected as a whole", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(NewJSONRPCErrorResponse(nil, InvalidRequest, "batch size too large", nil))
+		}))
+		defer server.Close()
+
+		_, err := New(server.URL).SendBatch(ctx, NewBatchRequest().Add("eth_blockNumber"))
+		var rpcErr *JSONRPCError
+		require.ErrorAs(t, err, &rpcErr)
+		assert.Equal(t, InvalidRequest, rpcErr.Code)
+		assert.Contains(t, err.Error(), "batch rejected")
+	})
+}
+
+func TestTypedBatchItemErrors(t *testing.T) {
+	server := newChunkServer(t)
+	c := New(server.URL, WithBatchOptions(&BatchOptions{MaxItems: 2}))
+
+	var a, b, d string
+	batch := NewTypedBatch().
+		Add(&a, "echo", "a").
+		Add(&b, "reject", "b").
+		Add(nil, "fail_chunk", "c").
+		Add(&d, "echo", "d")
+
+	err := c.SendTypedBatch(context.Background(), batch)
+	var httpErr *HTTPError
+	require.ErrorAs(t, err, &httpErr, "the failed chunk is reported")
+
+	// Results of the chunk that was sent are still bound.
+	assert.Equal(t, "a", a)
+	assert.NoError(t, batch.Err(0))
+
+	assert.ErrorIs(t, batch.Err(1), ErrNonceTooLow)
+	assert.Equal(t, ErrNonceTooLow, Classify(batch.Err(1)))
+	assert.Empty(t, b)
+
+	var itemErr *BatchItemError
+	require.ErrorAs(t, batch.Err(2), &itemErr)
+	assert.Equal(t, 2, itemErr.Index)
+	assert.Equal(t, "fail_chunk", itemErr.Method)
+	assert.ErrorAs(t, batch.Err(3), &httpErr)
+	assert.Empty(t, d)
+
+	errs := batch.Errors()
+	require.Len(t, errs, 3)
+	assert.Equal(t, []int{1, 2, 3}, []int{errs[0].Index, errs[1].Index, errs[2].Index})
+	assert.Equal(t, "batch item 1 (reject): RPC error -32000: nonce too low", errs[0].Error())
+}
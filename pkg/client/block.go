@@ -0,0 +1,184 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// BlockSelector selects the block a query runs against: a named tag, a block number,
+// or a block hash. Use one of the predefined tags, AtBlockNumber, or AtBlockHash.
+type BlockSelector struct {
+	tag  string
+	hash *common.Hash
+}
+
+// Named block tags.
+var (
+	// Latest selects the most recent block.
+	Latest = BlockSelector{tag: "latest"}
+
+	// Safe selects the most recent block that is safe from reorgs under normal conditions.
+	Safe = BlockSelector{tag: "safe"}
+
+	// Finalized selects the most recent finalized block.
+	Finalized = BlockSelector{tag: "finalized"}
+
+	// Pending selects the pending state, including transactions not yet in a block.
+	Pending = BlockSelector{tag: "pending"}
+
+	// Earliest selects the genesis block.
+	Earliest = BlockSelector{tag: "earliest"}
+)
+
+// AtBlockNumber selects the block with the given number.
+func AtBlockNumber(number uint64) BlockSelector {
+	return BlockSelector{tag: hexutil.EncodeUint64(number)}
+}
+
+// AtBlockHash selects the block with the given hash (EIP-1898).
+func AtBlockHash(hash common.Hash) BlockSelector {
+	return BlockSelector{hash: &hash}
+}
+
+// String returns the tag, hex block number, or block hash of the selector.
+func (s BlockSelector) String() string {
+	if s.hash != nil {
+		return s.hash.Hex()
+	}
+	if s.tag == "" {
+		return Latest.tag
+	}
+	return s.tag
+}
+
+// MarshalJSON encodes the selector as a block parameter.
+// Hash selectors use the EIP-1898 object form.
+func (s BlockSelector) MarshalJSON() ([]byte, error) {
+	if s.hash != nil {
+		return json.Marshal(map[string]common.Hash{"blockHash": *s.hash})
+	}
+	return json.Marshal(s.String())
+}
+
+// Block is a block as returned by eth_getBlockByNumber and eth_getBlockByHash.
+type Block struct {
+	Number           uint64
+	Hash             common.Hash
+	ParentHash       common.Hash
+	Timestamp        uint64
+	Miner            common.Address
+	GasLimit         uint64
+	GasUsed          uint64
+	BaseFeePerGas    *big.Int
+	StateRoot        common.Hash
+	TransactionsRoot common.Hash
+	ReceiptsRoot     common.Hash
+	LogsBloom        []byte
+	ExtraData        []byte
+	Size             uint64
+
+	// TxHashes holds the hashes of the block's transactions, in order.
+	TxHashes []common.Hash
+
+	// Transactions holds the block's transactions when the block was fetched with
+	// full transactions, and is nil otherwise.
+	Transactions []*Transaction
+}
+
+type blockJSON struct {
+	Number           hexutil.Uint64    `json:"number"`
+	Hash             common.Hash       `json:"hash"`
+	ParentHash       common.Hash       `json:"parentHash"`
+	Timestamp        hexutil.Uint64    `json:"timestamp"`
+	Miner            common.Address    `json:"miner"`
+	GasLimit         hexutil.Uint64    `json:"gasLimit"`
+	GasUsed          hexutil.Uint64    `json:"gasUsed"`
+	BaseFeePerGas    *hexutil.Big      `json:"baseFeePerGas"`
+	StateRoot        common.Hash       `json:"stateRoot"`
+	TransactionsRoot common.Hash       `json:"transactionsRoot"`
+	ReceiptsRoot     common.Hash       `json:"receiptsRoot"`
+	LogsBloom        hexutil.Bytes     `json:"logsBloom"`
+	ExtraData        hexutil.Bytes     `json:"extraData"`
+	Size             hexutil.Uint64    `json:"size"`
+	Transactions     []json.RawMessage `json:"transactions"`
+}
+
+// UnmarshalJSON decodes a block from its JSON-RPC representation.
+// The transactions field may hold either hashes or full transaction objects.
+func (b *Block) UnmarshalJSON(data []byte) error {
+	var dec blockJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+
+	*b = Block{
+		Number:           uint64(dec.Number),
+		Hash:             dec.Hash,
+		ParentHash:       dec.ParentHash,
+		Timestamp:        uint64(dec.Timestamp),
+		Miner:            dec.Miner,
+		GasLimit:         uint64(dec.GasLimit),
+		GasUsed:          uint64(dec.GasUsed),
+		BaseFeePerGas:    (*big.Int)(dec.BaseFeePerGas),
+		StateRoot:        dec.StateRoot,
+		TransactionsRoot: dec.TransactionsRoot,
+		ReceiptsRoot:     dec.ReceiptsRoot,
+		LogsBloom:        dec.LogsBloom,
+		ExtraData:        dec.ExtraData,
+		Size:             uint64(dec.Size),
+		TxHashes:         make([]common.Hash, 0, len(dec.Transactions)),
+	}
+
+	for i, raw := range dec.Transactions {
+		if len(raw) > 0 && raw[0] == '"' {
+			var hash common.Hash
+			if err := json.Unmarshal(raw, &hash); err != nil {
+				return fmt.Errorf("transaction %d: %w", i, err)
+			}
+			b.TxHashes = append(b.TxHashes, hash)
+			continue
+		}
+
+		var tx Transaction
+		if err := json.Unmarshal(raw, &tx); err != nil {
+			return fmt.Errorf("transaction %d: %w", i, err)
+		}
+		b.Transactions = append(b.Transactions, &tx)
+		b.TxHashes = append(b.TxHashes, tx.Hash)
+	}
+
+	return nil
+}
+
+// GetBlockByNumber gets a block by number or tag.
+// If fullTx is true, Block.Transactions is populated with decoded transactions.
+// Returns ErrNotFound if the block does not exist.
+func (c *Client) GetBlockByNumber(ctx context.Context, block BlockSelector, fullTx bool) (*Block, error) {
+	if block.hash != nil {
+		return c.GetBlockByHash(ctx, *block.hash, fullTx)
+	}
+	return c.getBlock(ctx, "eth_getBlockByNumber", block.String(), fullTx)
+}
+
+// GetBlockByHash gets a block by hash.
+// If fullTx is true, Block.Transactions is populated with decoded transactions.
+// Returns ErrNotFound if the block does not exist.
+func (c *Client) GetBlockByHash(ctx context.Context, hash common.Hash, fullTx bool) (*Block, error) {
+	return c.getBlock(ctx, "eth_getBlockByHash", hash.Hex(), fullTx)
+}
+
+func (c *Client) getBlock(ctx context.Context, method, id string, fullTx bool) (*Block, error) {
+	var block *Block
+	if err := c.call(ctx, &block, method, id, fullTx); err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("%w: block %s", ErrNotFound, id)
+	}
+	return block, nil
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempoxyz/tempo-go/pkg/transaction"
+)
+
+func testBlockJSON(transactions []interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"number":           "0x10",
+		"hash":             "0x2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0",
+		"parentHash":       "0x1a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0",
+		"timestamp":        "0x6553f100",
+		"miner":            "0x0000000000000000000000000000000000000000",
+		"gasLimit":         "0x1c9c380",
+		"gasUsed":          "0x5208",
+		"baseFeePerGas":    "0x2540be400",
+		"stateRoot":        "0x0000000000000000000000000000000000000000000000000000000000000001",
+		"transactionsRoot": "0x0000000000000000000000000000000000000000000000000000000000000002",
+		"receiptsRoot":     "0x0000000000000000000000000000000000000000000000000000000000000003",
+		"logsBloom":        "0x00",
+		"extraData":        "0x",
+		"size":             "0x220",
+		"transactions":     transactions,
+	}
+}
+
+func TestBlockSelector(t *testing.T) {
+	hash := common.HexToHash("0x2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0")
+
+	tests := []struct {
+		name     string
+		selector BlockSelector
+		want     string
+	}{
+		{name: "latest", selector: Latest, want: `"latest"`},
+		{name: "safe", selector: Safe, want: `"safe"`},
+		{name: "finalized", selector: Finalized, want: `"finalized"`},
+		{name: "pending", selector: Pending, want: `"pending"`},
+		{name: "earliest", selector: Earliest, want: `"earliest"`},
+		{name: "zero value", selector: BlockSelector{}, want: `"latest"`},
+		{name: "number", selector: AtBlockNumber(4660), want: `"0x1234"`},
+		{name: "hash", selector: AtBlockHash(hash), want: `{"blockHash":"` + hash.Hex() + `"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.selector)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestGetBlockByNumber(t *testing.T) {
+	t.Run("hashes only", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_getBlockByNumber": func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError) {
+				assert.Equal(t, []interface{}{"safe", false}, req.Params)
+				return testBlockJSON([]interface{}{testTxHash}), nil
+			},
+		})
+
+		block, err := New(server.URL).GetBlockByNumber(context.Background(), Safe, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(16), block.Number)
+		assert.Equal(t, uint64(0x6553f100), block.Timestamp)
+		assert.Equal(t, big.NewInt(10000000000), block.BaseFeePerGas)
+		assert.Equal(t, []common.Hash{common.HexToHash(testTxHash)}, block.TxHashes)
+		assert.Nil(t, block.Transactions)
+	})
+
+	t.Run("full transactions", func(t *testing.T) {
+		tempoTx := newSignedTx(t, transaction.ChainIDTempoTestnet)
+		tempoHash, err := tempoTx.Hash()
+		require.NoError(t, err)
+
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_getBlockByNumber": func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError) {
+				assert.Equal(t, []interface{}{"0x10", true}, req.Params)
+				return testBlockJSON([]interface{}{tempoTxJSON(t, tempoTx), dynamicFeeTxJSON()}), nil
+			},
+		})
+
+		block, err := New(server.URL).GetBlockByNumber(context.Background(), AtBlockNumber(16), true)
+		require.NoError(t, err)
+
+		require.Len(t, block.Transactions, 2)
+		assert.True(t, block.Transactions[0].IsTempo())
+		assert.NotNil(t, block.Transactions[0].Tempo)
+		assert.False(t, block.Transactions[1].IsTempo())
+		assert.Equal(t, []common.Hash{tempoHash, common.HexToHash(testTxHash)}, block.TxHashes)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_getBlockByNumber": result(nil),
+		})
+
+		_, err := New(server.URL).GetBlockByNumber(context.Background(), AtBlockNumber(1<<40), false)
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+}
+
+func TestGetBlockByHash(t *testing.T) {
+	hash := common.HexToHash("0x2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0")
+
+	server := newRPCServer(t, map[string]rpcHandler{
+		"eth_getBlockByHash": func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError) {
+			assert.Equal(t, []interface{}{hash.Hex(), false}, req.Params)
+			return testBlockJSON([]interface{}{}), nil
+		},
+	})
+	client := New(server.URL)
+
+	block, err := client.GetBlockByHash(context.Background(), hash, false)
+	require.NoError(t, err)
+	assert.Equal(t, hash, block.Hash)
+	assert.Empty(t, block.TxHashes)
+
+	// Hash selectors passed to GetBlockByNumber are routed to eth_getBlockByHash.
+	block, err = client.GetBlockByNumber(context.Background(), AtBlockHash(hash), false)
+	require.NoError(t, err)
+	assert.Equal(t, hash, block.Hash)
+}
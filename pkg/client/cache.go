@@ -0,0 +1,353 @@
+package client
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheOptions configures the read-through cache installed by WithCache.
+// Zero values are replaced with defaults.
+type CacheOptions struct {
+	// MaxEntries bounds the number of cached responses. The least recently used
+	// response is evicted first. Defaults to 10000.
+	MaxEntries int
+
+	// LatestTTL is how long responses that depend on the latest state, such as
+	// eth_blockNumber or an eth_call at "latest", are cached. Defaults to 1s.
+	LatestTTL time.Duration
+
+	// OnHit, OnMiss, OnCoalesce and OnEvict are called with the method of every cache
+	// hit, cache miss, call that shared the response of an identical call in flight,
+	// and evicted response. They must not block.
+	OnHit      func(method string)
+	OnMiss     func(method string)
+	OnCoalesce func(method string)
+	OnEvict    func(method string)
+}
+
+// WithCache installs a read-through cache for single read calls. Immutable responses
+// are kept until evicted: eth_chainId, blocks by hash, state queried at a block hash,
+// and receipts of finalized blocks. Responses that depend on the latest state are
+// kept for LatestTTL. Identical reads in flight at the same time are sent once and
+// share the response. Errors, null results, pending state, sends and batches are
+// never cached.
+//
+// The cache is the outermost interceptor, so other interceptors only see calls that
+// reach the network.
+func WithCache(opts *CacheOptions) Option {
+	o := CacheOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.MaxEntries <= 0 {
+		o.MaxEntries = 10000
+	}
+	if o.LatestTTL <= 0 {
+		o.LatestTTL = time.Second
+	}
+	return func(c *Client) {
+		cache := &responseCache{
+			client:  c,
+			opts:    o,
+			entries: make(map[string]*list.Element),
+			lru:     list.New(),
+			flights: make(map[string]*flight),
+		}
+		c.interceptors = append([]Interceptor{cache}, c.interceptors...)
+	}
+}
+
+// cachePolicy says how long the response to a call may be cached.
+type cachePolicy int
+
+const (
+	// cacheNever marks calls that are neither cached nor coalesced.
+	cacheNever cachePolicy = iota
+
+	// cacheUncached marks reads that are coalesced but not cached.
+	cacheUncached
+
+	// cacheLatest marks reads of the latest state, cached for LatestTTL.
+	cacheLatest
+
+	// cacheImmutable marks reads of data that never changes, cached until evicted.
+	cacheImmutable
+
+	// cacheReceipt marks receipt lookups, cached until evicted once finalized.
+	cacheReceipt
+)
+
+// blockParams holds the position of the block parameter of cacheable state reads.
+var blockParams = map[string]int{
+	"eth_call":                1,
+	"eth_getBalance":          1,
+	"eth_getCode":             1,
+	"eth_getTransactionCount": 1,
+	"eth_getStorageAt":        2,
+	"eth_getBlockByNumber":    0,
+}
+
+// classifyCall returns the cache policy of a request.
+func classifyCall(request *JSONRPCRequest) cachePolicy {
+	switch request.Method {
+	case "eth_chainId", "eth_getBlockByHash":
+		return cacheImmutable
+	case "eth_getTransactionReceipt":
+		return cacheReceipt
+	case "eth_blockNumber", "eth_gasPrice", "eth_maxPriorityFeePerGas":
+		return cacheLatest
+	}
+
+	index, ok := blockParams[request.Method]
+	if !ok {
+		return cacheNever
+	}
+	if index >= len(request.Params) {
+		// The block defaults to latest.
+		return cacheLatest
+	}
+	data, err := json.Marshal(request.Params[index])
+	if err != nil {
+		return cacheNever
+	}
+	var tag string
+	if json.Unmarshal(data, &tag) == nil {
+		switch {
+		case tag == "pending":
+			return cacheUncached
+		case len(tag) == 66 && strings.HasPrefix(tag, "0x"):
+			return cacheImmutable
+		default:
+			return cacheLatest
+		}
+	}
+	var selector struct {
+		BlockHash string `json:"blockHash"`
+	}
+	if json.Unmarshal(data, &selector) == nil && selector.BlockHash != "" {
+		return cacheImmutable
+	}
+	return cacheLatest
+}
+
+// responseCache is the interceptor installed by WithCache.
+type responseCache struct {
+	client *Client
+	opts   CacheOptions
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // of *cacheEntry
+	lru     *list.List               // most recently used first
+	flights map[string]*flight
+}
+
+type cacheEntry struct {
+	key      string
+	method   string
+	response *JSONRPCResponse
+	expires  time.Time // zero for immutable responses
+}
+
+// flight is a call in progress whose response is shared by identical calls.
+type flight struct {
+	done     chan struct{}
+	response *JSONRPCResponse
+	err      error
+}
+
+// Intercept serves single read calls from the cache or coalesces them with identical
+// calls in flight.
+func (k *responseCache) Intercept(ctx context.Context, call *Invocation, next Invoker) ([]*JSONRPCResponse, error) {
+	if call.Batch || len(call.Requests) != 1 {
+		return next(ctx, call)
+	}
+	request := call.Requests[0]
+	policy := classifyCall(request)
+	if policy == cacheNever {
+		return next(ctx, call)
+	}
+	params, err := json.Marshal(request.Params)
+	if err != nil {
+		return next(ctx, call)
+	}
+	key := request.Method + string(params)
+
+	k.mu.Lock()
+	if response := k.lookup(key, time.Now()); response != nil {
+		k.mu.Unlock()
+		k.observe(k.opts.OnHit, request.Method)
+		return []*JSONRPCResponse{withID(response, request.ID)}, nil
+	}
+	if f, ok := k.flights[key]; ok {
+		k.mu.Unlock()
+		k.observe(k.opts.OnCoalesce, request.Method)
+		return k.wait(ctx, call, next, f)
+	}
+	f := &flight{done: make(chan struct{})}
+	k.flights[key] = f
+	k.mu.Unlock()
+	k.observe(k.opts.OnMiss, request.Method)
+
+	responses, err := next(ctx, call)
+	if err == nil && len(responses) == 1 {
+		f.response = responses[0]
+	} else if err == nil {
+		err = errors.New("unexpected number of responses")
+	}
+	f.err = err
+
+	var expires time.Time
+	cacheable := false
+	if f.response != nil {
+		expires, cacheable = k.expiry(ctx, policy, f.response, next)
+	}
+	k.mu.Lock()
+	delete(k.flights, key)
+	if cacheable {
+		k.store(key, request.Method, f.response, expires)
+	}
+	k.mu.Unlock()
+	close(f.done)
+
+	if err != nil {
+		return nil, err
+	}
+	return []*JSONRPCResponse{withID(f.response, request.ID)}, nil
+}
+
+// wait waits for the response of an identical call in flight. If that call was
+// cancelled while ctx is still live, the call is sent again.
+func (k *responseCache) wait(ctx context.Context, call *Invocation, next Invoker, f *flight) ([]*JSONRPCResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-f.done:
+	}
+	if f.err != nil {
+		if errors.Is(f.err, context.Canceled) || errors.Is(f.err, context.DeadlineExceeded) {
+			return next(ctx, call)
+		}
+		return nil, f.err
+	}
+	return []*JSONRPCResponse{withID(f.response, call.Requests[0].ID)}, nil
+}
+
+// lookup returns the cached response for key, or nil. It must be called with mu held.
+func (k *responseCache) lookup(key string, now time.Time) *JSONRPCResponse {
+	elem, ok := k.entries[key]
+	if !ok {
+		return nil
+	}
+	entry := elem.Value.(*cacheEntry)
+	if !entry.expires.IsZero() && now.After(entry.expires) {
+		k.lru.Remove(elem)
+		delete(k.entries, key)
+		return nil
+	}
+	k.lru.MoveToFront(elem)
+	return entry.response
+}
+
+// expiry returns when a response to a call with the given policy expires, zero for
+// never, and whether it may be cached at all.
+func (k *responseCache) expiry(ctx context.Context, policy cachePolicy, response *JSONRPCResponse, next Invoker) (time.Time, bool) {
+	if response.Error != nil || response.isNull() {
+		return time.Time{}, false
+	}
+	switch policy {
+	case cacheImmutable:
+		return time.Time{}, true
+	case cacheLatest:
+		return time.Now().Add(k.opts.LatestTTL), true
+	case cacheReceipt:
+		if k.isFinal(ctx, response, next) {
+			return time.Time{}, true
+		}
+		return time.Now().Add(k.opts.LatestTTL), true
+	}
+	return time.Time{}, false
+}
+
+// store caches a response and evicts the least recently used responses beyond
+// MaxEntries. It must be called with mu held.
+func (k *responseCache) store(key, method string, response *JSONRPCResponse, expires time.Time) {
+	if elem, ok := k.entries[key]; ok {
+		k.lru.Remove(elem)
+	}
+	k.entries[key] = k.lru.PushFront(&cacheEntry{key: key, method: method, response: response, expires: expires})
+	for k.lru.Len() > k.opts.MaxEntries {
+		oldest := k.lru.Back()
+		entry := oldest.Value.(*cacheEntry)
+		k.lru.Remove(oldest)
+		delete(k.entries, entry.key)
+		k.observe(k.opts.OnEvict, entry.method)
+	}
+}
+
+// isFinal reports whether the block of a receipt is finalized. The finalized block is
+// looked up through the cache, so it is fetched at most once per LatestTTL.
+func (k *responseCache) isFinal(ctx context.Context, receipt *JSONRPCResponse, next Invoker) bool {
+	var included struct {
+		BlockNumber *HexUint64 `json:"blockNumber"`
+	}
+	if err := receipt.DecodeResult(&included); err != nil || included.BlockNumber == nil {
+		return false
+	}
+
+	request := k.client.newRequest("eth_getBlockByNumber", Finalized, false)
+	responses, err := k.Intercept(ctx, &Invocation{Requests: []*JSONRPCRequest{request}}, next)
+	if err != nil || len(responses) != 1 || responses[0].Error != nil || responses[0].isNull() {
+		return false
+	}
+	var finalized struct {
+		Number HexUint64 `json:"number"`
+	}
+	if err := responses[0].DecodeResult(&finalized); err != nil {
+		return false
+	}
+	return *included.BlockNumber <= finalized.Number
+}
+
+func (k *responseCache) observe(hook func(string), method string) {
+	if hook != nil {
+		hook(method)
+	}
+}
+
+// withID returns a copy of a shared response carrying the ID of the caller's request.
+// The result is copied as well, so callers may modify it without affecting the cache.
+func withID(response *JSONRPCResponse, id interface{}) *JSONRPCResponse {
+	copied := *response
+	copied.ID = id
+	copied.Result = copyResult(response.Result)
+	if response.RawResult != nil {
+		copied.RawResult = append(json.RawMessage(nil), response.RawResult...)
+	}
+	return &copied
+}
+
+// copyResult returns a deep copy of a decoded JSON value. Values of other types, set
+// by interceptors, are returned as is.
+func copyResult(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			copied[key] = copyResult(value)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, value := range v {
+			copied[i] = copyResult(value)
+		}
+		return copied
+	default:
+		return v
+	}
+}
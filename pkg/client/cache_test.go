@@ -0,0 +1,301 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cacheNode counts the calls of every method that reach the server.
+type cacheNode struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (n *cacheNode) count(method string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.calls[method]
+}
+
+// newCacheClient starts a server with handlers and returns a client caching with opts.
+func newCacheClient(t *testing.T, handlers map[string]rpcHandler, opts *CacheOptions, extra ...Option) (*Client, *cacheNode) {
+	node := &cacheNode{calls: make(map[string]int)}
+	counted := make(map[string]rpcHandler, len(handlers))
+	for method, handler := range handlers {
+		method, handler := method, handler
+		counted[method] = func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError) {
+			node.mu.Lock()
+			node.calls[method]++
+			node.mu.Unlock()
+			return handler(t, req)
+		}
+	}
+	server := newRPCServer(t, counted)
+	return New(server.URL, append([]Option{WithCache(opts)}, extra...)...), node
+}
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	blockHash := common.HexToHash("0xb10c")
+
+	t.Run("immutable responses", func(t *testing.T) {
+		var hits, misses atomic.Int32
+		c, node := newCacheClient(t, map[string]rpcHandler{
+			"eth_chainId":        result("0xa5bf"),
+			"eth_getBlockByHash": result(map[string]interface{}{"hash": blockHash, "number": "0x5"}),
+			"eth_getBalance":     result("0x64"),
+		}, &CacheOptions{
+			LatestTTL: time.Millisecond,
+			OnHit:     func(string) { hits.Add(1) },
+			OnMiss:    func(string) { misses.Add(1) },
+		})
+
+		for i := 0; i < 3; i++ {
+			response, err := c.SendRequest(ctx, "eth_chainId")
+			require.NoError(t, err)
+			assert.Equal(t, "0xa5bf", response.Result)
+
+			block, err := c.GetBlockByHash(ctx, blockHash, false)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(5), block.Number)
+
+			_, err = c.GetBalance(ctx, common.Address{}, AtBlockHash(blockHash))
+			require.NoError(t, err)
+			time.Sleep(2 * time.Millisecond)
+		}
+		assert.Equal(t, 1, node.count("eth_chainId"))
+		assert.Equal(t, 1, node.count("eth_getBlockByHash"))
+		assert.Equal(t, 1, node.count("eth_getBalance"))
+		assert.Equal(t, int32(6), hits.Load())
+		assert.Equal(t, int32(3), misses.Load())
+	})
+
+	t.Run("hits are copies", func(t *testing.T) {
+		c, node := newCacheClient(t, map[string]rpcHandler{
+			"eth_getBlockByHash": result(map[string]interface{}{
+				"hash":         blockHash,
+				"number":       "0x5",
+				"transactions": []interface{}{"0x01"},
+			}),
+		}, nil)
+
+		for i := 0; i < 2; i++ {
+			response, err := c.SendRequest(ctx, "eth_getBlockByHash", blockHash, false)
+			require.NoError(t, err)
+			block := response.Result.(map[string]interface{})
+			assert.Equal(t, "0x5", block["number"])
+			assert.Equal(t, []interface{}{"0x01"}, block["transactions"])
+
+			block["number"] = "0x6"
+			block["transactions"].([]interface{})[0] = "0x02"
+		}
+		assert.Equal(t, 1, node.count("eth_getBlockByHash"))
+	})
+
+	t.Run("latest responses expire", func(t *testing.T) {
+		c, node := newCacheClient(t, map[string]rpcHandler{
+			"eth_blockNumber": result("0x10"),
+			"eth_getBalance":  result("0x64"),
+		}, &CacheOptions{LatestTTL: 50 * time.Millisecond})
+
+		for i := 0; i < 2; i++ {
+			_, err := c.GetBlockNumber(ctx)
+			require.NoError(t, err)
+			_, err = c.GetBalance(ctx, common.Address{}, Latest)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 1, node.count("eth_blockNumber"))
+		assert.Equal(t, 1, node.count("eth_getBalance"))
+
+		time.Sleep(60 * time.Millisecond)
+		_, err := c.GetBlockNumber(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, node.count("eth_blockNumber"))
+	})
+
+	t.Run("never cached", func(t *testing.T) {
+		c, node := newCacheClient(t, map[string]rpcHandler{
+			"eth_getBalance":            result("0x64"),
+			"eth_sendRawTransaction":    result("0xabc"),
+			"eth_getTransactionReceipt": result(nil),
+			"eth_getCode": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+				return nil, &JSONRPCError{Code: InternalError, Message: "boom"}
+			},
+		}, nil)
+
+		for i := 0; i < 2; i++ {
+			_, err := c.GetBalance(ctx, common.Address{}, Pending)
+			require.NoError(t, err)
+			_, err = c.SendRawTransaction(ctx, "0x76")
+			require.NoError(t, err)
+			_, err = c.GetTransactionReceipt(ctx, common.Hash{})
+			assert.ErrorIs(t, err, ErrNotFound)
+			_, err = c.GetCode(ctx, common.Address{}, Latest)
+			assert.Error(t, err)
+		}
+		assert.Equal(t, 2, node.count("eth_getBalance"))
+		assert.Equal(t, 2, node.count("eth_sendRawTransaction"))
+		assert.Equal(t, 2, node.count("eth_getTransactionReceipt"))
+		assert.Equal(t, 2, node.count("eth_getCode"))
+	})
+
+	t.Run("receipts are cached once finalized", func(t *testing.T) {
+		receipt := func(number string) rpcHandler {
+			return result(map[string]interface{}{"transactionHash": common.HexToHash("0x1"), "blockNumber": number, "status": "0x1"})
+		}
+		finalized := result(map[string]interface{}{"number": "0x10"})
+
+		c, node := newCacheClient(t, map[string]rpcHandler{
+			"eth_getTransactionReceipt": receipt("0x8"),
+			"eth_getBlockByNumber":      finalized,
+		}, &CacheOptions{LatestTTL: 20 * time.Millisecond})
+		for i := 0; i < 3; i++ {
+			got, err := c.GetTransactionReceipt(ctx, common.HexToHash("0x1"))
+			require.NoError(t, err)
+			assert.Equal(t, uint64(8), uint64(got.BlockNumber))
+			time.Sleep(25 * time.Millisecond)
+		}
+		assert.Equal(t, 1, node.count("eth_getTransactionReceipt"))
+
+		c, node = newCacheClient(t, map[string]rpcHandler{
+			"eth_getTransactionReceipt": receipt("0x20"),
+			"eth_getBlockByNumber":      finalized,
+		}, &CacheOptions{LatestTTL: 20 * time.Millisecond})
+		_, err := c.GetTransactionReceipt(ctx, common.HexToHash("0x1"))
+		require.NoError(t, err)
+		_, err = c.GetTransactionReceipt(ctx, common.HexToHash("0x1"))
+		require.NoError(t, err)
+		assert.Equal(t, 1, node.count("eth_getTransactionReceipt"), "recent receipts are cached briefly")
+
+		time.Sleep(25 * time.Millisecond)
+		_, err = c.GetTransactionReceipt(ctx, common.HexToHash("0x1"))
+		require.NoError(t, err)
+		assert.Equal(t, 2, node.count("eth_getTransactionReceipt"))
+	})
+
+	t.Run("concurrent reads are coalesced", func(t *testing.T) {
+		release := make(chan struct{})
+		var coalesced atomic.Int32
+		c, node := newCacheClient(t, map[string]rpcHandler{
+			"eth_getBalance": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+				<-release
+				return "0x64", nil
+			},
+		}, &CacheOptions{OnCoalesce: func(string) { coalesced.Add(1) }})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				// Pending state is never cached, but identical reads still share a call.
+				balance, err := c.GetBalance(ctx, common.Address{}, Pending)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(100), balance.Int64())
+			}()
+		}
+		assert.Eventually(t, func() bool { return coalesced.Load() == 4 }, 5*time.Second, time.Millisecond)
+		close(release)
+		wg.Wait()
+		assert.Equal(t, 1, node.count("eth_getBalance"))
+	})
+
+	t.Run("coalesced call survives the cancellation of the first caller", func(t *testing.T) {
+		var calls atomic.Int32
+		c, _ := newCacheClient(t, map[string]rpcHandler{
+			"eth_chainId": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+				if calls.Add(1) == 1 {
+					time.Sleep(100 * time.Millisecond)
+				}
+				return "0xa5bf", nil
+			},
+		}, nil)
+
+		firstCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		done := make(chan error)
+		go func() {
+			_, err := c.SendRequest(firstCtx, "eth_chainId")
+			done <- err
+		}()
+		time.Sleep(5 * time.Millisecond)
+
+		response, err := c.SendRequest(ctx, "eth_chainId")
+		require.NoError(t, err)
+		assert.Equal(t, "0xa5bf", response.Result)
+		assert.Error(t, <-done)
+	})
+
+	t.Run("least recently used responses are evicted", func(t *testing.T) {
+		var evicted []string
+		c, node := newCacheClient(t, map[string]rpcHandler{
+			"eth_chainId":        result("0xa5bf"),
+			"eth_getBlockByHash": result(map[string]interface{}{"number": "0x1"}),
+			"eth_blockNumber":    result("0x1"),
+		}, &CacheOptions{MaxEntries: 2, LatestTTL: time.Minute, OnEvict: func(method string) { evicted = append(evicted, method) }})
+
+		_, err := c.SendRequest(ctx, "eth_chainId")
+		require.NoError(t, err)
+		_, err = c.GetBlockByHash(ctx, blockHash, false)
+		require.NoError(t, err)
+		_, err = c.SendRequest(ctx, "eth_chainId") // now the most recently used
+		require.NoError(t, err)
+		_, err = c.GetBlockNumber(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"eth_getBlockByHash"}, evicted)
+
+		_, err = c.SendRequest(ctx, "eth_chainId")
+		require.NoError(t, err)
+		assert.Equal(t, 1, node.count("eth_chainId"))
+		_, err = c.GetBlockByHash(ctx, blockHash, false)
+		require.NoError(t, err)
+		assert.Equal(t, 2, node.count("eth_getBlockByHash"))
+	})
+
+	t.Run("interceptors only see calls that reach the node", func(t *testing.T) {
+		var timed atomic.Int32
+		c, _ := newCacheClient(t, map[string]rpcHandler{"eth_chainId": result("0xa5bf")}, nil,
+			WithInterceptors(TimingInterceptor(func(CallTiming) { timed.Add(1) })))
+
+		for i := 0; i < 3; i++ {
+			response, err := c.SendRequest(ctx, "eth_chainId")
+			require.NoError(t, err)
+			assert.Equal(t, "0xa5bf", response.Result)
+		}
+		assert.Equal(t, int32(1), timed.Load())
+	})
+}
+
+func TestClassifyCall(t *testing.T) {
+	hash := common.HexToHash("0xb10c")
+	tests := []struct {
+		name    string
+		request *JSONRPCRequest
+		want    cachePolicy
+	}{
+		{"chain ID", NewJSONRPCRequest(1, "eth_chainId"), cacheImmutable},
+		{"block by hash", NewJSONRPCRequest(1, "eth_getBlockByHash", hash, false), cacheImmutable},
+		{"receipt", NewJSONRPCRequest(1, "eth_getTransactionReceipt", hash), cacheReceipt},
+		{"block number", NewJSONRPCRequest(1, "eth_blockNumber"), cacheLatest},
+		{"call at latest", NewJSONRPCRequest(1, "eth_call", map[string]interface{}{}, Latest), cacheLatest},
+		{"call without block", NewJSONRPCRequest(1, "eth_call", map[string]interface{}{}), cacheLatest},
+		{"call at number", NewJSONRPCRequest(1, "eth_call", map[string]interface{}{}, AtBlockNumber(5)), cacheLatest},
+		{"call at hash", NewJSONRPCRequest(1, "eth_call", map[string]interface{}{}, AtBlockHash(hash)), cacheImmutable},
+		{"storage at hash string", NewJSONRPCRequest(1, "eth_getStorageAt", common.Address{}, "0x0", hash.Hex()), cacheImmutable},
+		{"balance at pending", NewJSONRPCRequest(1, "eth_getBalance", common.Address{}, Pending), cacheUncached},
+		{"send", NewJSONRPCRequest(1, "eth_sendRawTransaction", "0x76"), cacheNever},
+		{"logs", NewJSONRPCRequest(1, "eth_getLogs", map[string]interface{}{}), cacheNever},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, classifyCall(tt.request))
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/tempoxyz/tempo-go/pkg/transaction"
+)
+
+// RevertError is returned when a simulated call or gas estimate reverts.
+type RevertError struct {
+	// Message is the error message reported by the node.
+	Message string
+
+	// Data is the raw revert data returned by the contract, if any.
+	Data []byte
+
+	// Reason is the decoded revert reason: the message of Error(string) reverts,
+	// the meaning of Panic(uint256) reverts, or the rendered custom error. Empty if
+	// the revert data was not recognized.
+	Reason string
+
+	// Revert is the decoded revert data, or nil if the node returned none.
+	Revert *Revert
+
+	rpcErr *JSONRPCError
+}
+
+// Error implements the error interface for RevertError.
+func (e *RevertError) Error() string {
+	switch {
+	case e.Reason != "":
+		return "execution reverted: " + e.Reason
+	case len(e.Data) > 0:
+		return "execution reverted with data " + hexutil.Encode(e.Data)
+	default:
+		return "execution reverted"
+	}
+}
+
+// Unwrap returns the underlying JSON-RPC error.
+func (e *RevertError) Unwrap() error {
+	return e.rpcErr
+}
+
+// asRevertError converts a JSON-RPC revert error into a *RevertError with revert data
+// decoded by registry. Other errors are returned unchanged.
+func asRevertError(err error, registry *ErrorRegistry) error {
+	var rpcErr *JSONRPCError
+	if !errors.As(err, &rpcErr) {
+		return err
+	}
+	if !errors.Is(rpcErr, ErrExecutionReverted) {
+		return err
+	}
+
+	revertErr := &RevertError{Message: rpcErr.Message, rpcErr: rpcErr}
+	if dataHex, ok := rpcErr.Data.(string); ok {
+		if data, decodeErr := hexutil.Decode(dataHex); decodeErr == nil {
+			revertErr.Data = data
+		}
+	}
+	revertErr.Revert = registry.Decode(revertErr.Data)
+	if revertErr.Revert != nil && revertErr.Revert.Kind != RevertUnknown {
+		revertErr.Reason = revertErr.Revert.String()
+	}
+	return revertErr
+}
+
+// Call simulates a Tempo transaction against the state of the given block without
+// broadcasting it, and returns the output of the last call. The transaction does not
+// need to be signed; set tx.From to simulate on behalf of a sender.
+// If execution reverts, the error is a *RevertError carrying the decoded revert data.
+func (c *Client) Call(ctx context.Context, tx *transaction.Tx, block BlockSelector) ([]byte, error) {
+	var output hexutil.Bytes
+	if err := c.call(ctx, &output, "eth_call", tempoCallArgs(tx), block); err != nil {
+		return nil, asRevertError(err, c.errorRegistry())
+	}
+	return output, nil
+}
+
+// EstimateGas estimates the gas limit needed to execute all calls of a Tempo transaction.
+// The transaction does not need to be signed; set tx.From to estimate on behalf of a sender.
+// If execution reverts, the error is a *RevertError carrying the decoded revert data.
+// Any gas limit already set on tx is ignored, so the estimate is not capped by it.
+func (c *Client) EstimateGas(ctx context.Context, tx *transaction.Tx) (uint64, error) {
+	unlimited := *tx
+	unlimited.Gas = 0
+
+	var gas hexutil.Uint64
+	if err := c.call(ctx, &gas, "eth_estimateGas", tempoCallArgs(&unlimited)); err != nil {
+		return 0, asRevertError(err, c.errorRegistry())
+	}
+	return uint64(gas), nil
+}
+
+// tempoCallArgs renders a Tempo transaction in the node's call-object format.
+// Unset optional fields are omitted so the node can fill in its defaults.
+func tempoCallArgs(tx *transaction.Tx) map[string]interface{} {
+	calls := make([]map[string]interface{}, 0, len(tx.Calls))
+	for _, call := range tx.Calls {
+		arg := map[string]interface{}{
+			"input": hexutil.Bytes(call.Data),
+		}
+		if call.To != nil {
+			arg["to"] = *call.To
+		}
+		if call.Value != nil {
+			arg["value"] = (*hexutil.Big)(call.Value)
+		}
+		calls = append(calls, arg)
+	}
+
+	args := map[string]interface{}{
+		"type":  hexutil.Uint64(transaction.TxTypeTempo),
+		"calls": calls,
+	}
+	if tx.From != (common.Address{}) {
+		args["from"] = tx.From
+	}
+	if tx.ChainID != nil && tx.ChainID.Sign() > 0 {
+		args["chainId"] = (*hexutil.Big)(tx.ChainID)
+	}
+	if tx.Gas > 0 {
+		args["gas"] = hexutil.Uint64(tx.Gas)
+	}
+	if tx.MaxFeePerGas != nil && tx.MaxFeePerGas.Sign() > 0 {
+		args["maxFeePerGas"] = (*hexutil.Big)(tx.MaxFeePerGas)
+	}
+	if tx.MaxPriorityFeePerGas != nil && tx.MaxPriorityFeePerGas.Sign() > 0 {
+		args["maxPriorityFeePerGas"] = (*hexutil.Big)(tx.MaxPriorityFeePerGas)
+	}
+	if tx.NonceKey != nil && tx.NonceKey.Sign() > 0 {
+		args["nonceKey"] = (*hexutil.Big)(tx.NonceKey)
+	}
+	if tx.Nonce > 0 {
+		args["nonce"] = hexutil.Uint64(tx.Nonce)
+	}
+	if tx.ValidBefore > 0 {
+		args["validBefore"] = hexutil.Uint64(tx.ValidBefore)
+	}
+	if tx.ValidAfter > 0 {
+		args["validAfter"] = hexutil.Uint64(tx.ValidAfter)
+	}
+	if tx.FeeToken != (common.Address{}) {
+		args["feeToken"] = tx.FeeToken
+	}
+	if len(tx.AccessList) > 0 {
+		args["accessList"] = tx.AccessList
+	}
+	if tx.AwaitingFeePayer || tx.FeePayerSignature != nil {
+		args["feePayer"] = true
+	}
+
+	return args
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempoxyz/tempo-go/pkg/transaction"
+)
+
+// revertData is the ABI encoding of Error("insufficient balance").
+const revertData = "0x08c379a0" +
+	"0000000000000000000000000000000000000000000000000000000000000020" +
+	"0000000000000000000000000000000000000000000000000000000000000014" +
+	"696e73756666696369656e742062616c616e6365000000000000000000000000"
+
+func TestCall(t *testing.T) {
+	target := common.HexToAddress("0x20c0000000000000000000000000000000000001")
+	tx := transaction.NewBuilder(big.NewInt(transaction.ChainIDTempoTestnet)).
+		AddCall(target, big.NewInt(0), []byte{0x70, 0xa0, 0x82, 0x31}).
+		SetNonceKey(big.NewInt(7)).
+		SetFeeToken(transaction.AlphaUSDAddress).
+		Build()
+	tx.From = testAccount
+
+	server := newRPCServer(t, map[string]rpcHandler{
+		"eth_call": func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError) {
+			args := req.Params[0].(map[string]interface{})
+			assert.Equal(t, "0x76", args["type"])
+			assert.Equal(t, "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266", args["from"])
+			assert.Equal(t, "0x7", args["nonceKey"])
+			assert.Equal(t, "0x20c0000000000000000000000000000000000001", args["feeToken"])
+			assert.NotContains(t, args, "gas")
+			assert.NotContains(t, args, "feePayer")
+			assert.Equal(t, []interface{}{map[string]interface{}{
+				"to":    "0x20c0000000000000000000000000000000000001",
+				"value": "0x0",
+				"input": "0x70a08231",
+			}}, args["calls"])
+			assert.Equal(t, "pending", req.Params[1])
+			return "0x2a", nil
+		},
+	})
+
+	output, err := New(server.URL).Call(context.Background(), tx, Pending)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x2a}, output)
+}
+
+func TestEstimateGas(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		tx := transaction.NewBuilder(big.NewInt(transaction.ChainIDTempoTestnet)).
+			AddCall(common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"), big.NewInt(0), nil).
+			Build()
+		tx.AwaitingFeePayer = true
+
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_estimateGas": func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError) {
+				require.Len(t, req.Params, 1)
+				args := req.Params[0].(map[string]interface{})
+				assert.Equal(t, true, args["feePayer"])
+				return "0xc350", nil
+			},
+		})
+
+		gas, err := New(server.URL).EstimateGas(context.Background(), tx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(50000), gas)
+	})
+
+	t.Run("ignores the current gas limit", func(t *testing.T) {
+		tx := transaction.NewBuilder(big.NewInt(transaction.ChainIDTempoTestnet)).
+			SetGas(21000).
+			AddCall(common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"), big.NewInt(0), nil).
+			Build()
+
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_estimateGas": func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError) {
+				args := req.Params[0].(map[string]interface{})
+				assert.NotContains(t, args, "gas")
+				return "0xc350", nil
+			},
+		})
+
+		gas, err := New(server.URL).EstimateGas(context.Background(), tx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(50000), gas)
+		assert.Equal(t, uint64(21000), tx.Gas)
+	})
+
+	t.Run("revert", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_estimateGas": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+				// The reason in the message must not be mistaken for a balance error.
+				return nil, &JSONRPCError{Code: 3, Message: "execution reverted: insufficient balance", Data: revertData}
+			},
+		})
+
+		_, err := New(server.URL).EstimateGas(context.Background(), transaction.New())
+		require.Error(t, err)
+
+		var revertErr *RevertError
+		require.True(t, errors.As(err, &revertErr))
+		assert.Equal(t, "insufficient balance", revertErr.Reason)
+		assert.Equal(t, "execution reverted: insufficient balance", revertErr.Error())
+		assert.False(t, errors.Is(err, ErrInsufficientFeeTokenBalance))
+
+		var rpcErr *JSONRPCError
+		require.True(t, errors.As(err, &rpcErr))
+		assert.Equal(t, 3, rpcErr.Code)
+	})
+
+	t.Run("other error", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_estimateGas": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+				return nil, &JSONRPCError{Code: -32000, Message: "gas required exceeds allowance"}
+			},
+		})
+
+		_, err := New(server.URL).EstimateGas(context.Background(), transaction.New())
+		require.Error(t, err)
+
+		var revertErr *RevertError
+		assert.False(t, errors.As(err, &revertErr))
+	})
+}
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/tempoxyz/tempo-go/pkg/chains"
+	"github.com/tempoxyz/tempo-go/pkg/transaction"
+)
+
+// WithChainID pins the client to a network. Before the first request, the client
+// checks the node's eth_chainId and fails every call with ErrChainIDMismatch if it
+// differs, so a misconfigured URL cannot send transactions to the wrong network.
+// Raw transactions whose chain ID differs are refused without being sent. This covers
+// Tempo transactions and Ethereum legacy and typed ones; legacy transactions signed
+// without a chain ID are left to the node.
+func WithChainID(chainID *big.Int) Option {
+	return func(c *Client) {
+		c.expectedChainID = new(big.Int).Set(chainID)
+	}
+}
+
+// WithChain pins the client to a network from the chains registry, as WithChainID.
+func WithChain(chain *chains.Chain) Option {
+	return WithChainID(chain.ChainID())
+}
+
+// checkChain verifies that the node and the transactions of a call are on the chain
+// set with WithChainID. The eth_chainId lookup is cached by ChainID, so only the
+// first call reaches the node.
+func (c *Client) checkChain(ctx context.Context, call *Invocation) error {
+	if c.expectedChainID == nil || isChainIDCall(call) {
+		return nil
+	}
+
+	chainID, err := c.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to verify chain ID: %w", err)
+	}
+	if chainID.Cmp(c.expectedChainID) != 0 {
+		return fmt.Errorf("%w: client expects %s, node has %s", ErrChainIDMismatch, c.expectedChainID, chainID)
+	}
+
+	for _, request := range call.Requests {
+		if !isSendMethod(request.Method) || len(request.Params) == 0 {
+			continue
+		}
+		serializedTx, ok := request.Params[0].(string)
+		if !ok {
+			continue
+		}
+		// Transactions that do not decode are left for the node to reject.
+		txChainID, ok := rawTxChainID(serializedTx)
+		if !ok {
+			continue
+		}
+		if txChainID.Cmp(c.expectedChainID) != 0 {
+			return fmt.Errorf("%w: transaction has %s, client expects %s", ErrChainIDMismatch, txChainID, c.expectedChainID)
+		}
+	}
+	return nil
+}
+
+// rawTxChainID returns the chain ID of a serialized transaction, and false if it does
+// not decode or carries no chain ID. Tempo transactions are decoded in full; for
+// Ethereum transactions only the field holding the chain ID is read.
+func rawTxChainID(serializedTx string) (*big.Int, bool) {
+	if strings.HasPrefix(strings.TrimPrefix(serializedTx, "0x"), "76") {
+		tx, err := transaction.Deserialize(serializedTx)
+		if err != nil || tx.ChainID == nil {
+			return nil, false
+		}
+		return tx.ChainID, true
+	}
+
+	raw, err := hexutil.Decode(serializedTx)
+	if err != nil || len(raw) == 0 {
+		return nil, false
+	}
+
+	// Legacy transactions are an RLP list whose v field encodes the chain ID per
+	// EIP-155: v = chainID*2 + 35 or 36.
+	if raw[0] >= 0xc0 {
+		var fields []rlp.RawValue
+		if err := rlp.DecodeBytes(raw, &fields); err != nil || len(fields) != 9 {
+			return nil, false
+		}
+		v := new(big.Int)
+		if err := rlp.DecodeBytes(fields[6], v); err != nil || v.Cmp(big.NewInt(35)) < 0 {
+			return nil, false
+		}
+		return v.Rsh(v.Sub(v, big.NewInt(35)), 1), true
+	}
+
+	// Typed transactions (EIP-2718) are a type byte followed by an RLP list that
+	// starts with the chain ID.
+	if raw[0] > 0x7f {
+		return nil, false
+	}
+	var fields []rlp.RawValue
+	if err := rlp.DecodeBytes(raw[1:], &fields); err != nil || len(fields) == 0 {
+		return nil, false
+	}
+	chainID := new(big.Int)
+	if err := rlp.DecodeBytes(fields[0], chainID); err != nil {
+		return nil, false
+	}
+	return chainID, true
+}
+
+// isChainIDCall reports whether call only asks for eth_chainId, which is how
+// checkChain itself reaches the node.
+func isChainIDCall(call *Invocation) bool {
+	for _, request := range call.Requests {
+		if request.Method != "eth_chainId" {
+			return false
+		}
+	}
+	return len(call.Requests) > 0
+}
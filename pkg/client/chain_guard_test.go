@@ -0,0 +1,201 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempoxyz/tempo-go/pkg/chains"
+	"github.com/tempoxyz/tempo-go/pkg/transaction"
+)
+
+func TestWithChainID(t *testing.T) {
+	ctx := context.Background()
+	testnetTx, err := transaction.Serialize(newSignedTx(t, transaction.ChainIDTempoTestnet), nil)
+	require.NoError(t, err)
+	mainnetTx, err := transaction.Serialize(newSignedTx(t, transaction.ChainIDTempo), nil)
+	require.NoError(t, err)
+
+	t.Run("checks the node once", func(t *testing.T) {
+		var chainIDCalls atomic.Int32
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_chainId": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+				chainIDCalls.Add(1)
+				return "0xa5bd", nil
+			},
+			"eth_blockNumber": result("0x10"),
+		})
+		c := New(server.URL, WithChain(chains.Testnet))
+
+		for i := 0; i < 3; i++ {
+			number, err := c.GetBlockNumber(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(16), number)
+		}
+		assert.Equal(t, int32(1), chainIDCalls.Load())
+	})
+
+	t.Run("slow lookup does not block other calls", func(t *testing.T) {
+		started, release := make(chan struct{}, 1), make(chan struct{})
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_chainId": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+				select {
+				case started <- struct{}{}:
+				default:
+				}
+				<-release
+				return "0xa5bd", nil
+			},
+			"eth_blockNumber": result("0x10"),
+		})
+		var once sync.Once
+		unblock := func() { once.Do(func() { close(release) }) }
+		t.Cleanup(unblock)
+		c := New(server.URL, WithChain(chains.Testnet))
+
+		first := make(chan error, 1)
+		go func() {
+			_, err := c.GetBlockNumber(ctx)
+			first <- err
+		}()
+		<-started
+
+		short, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		done := make(chan error, 1)
+		go func() {
+			_, err := c.GetBlockNumber(short)
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+		case <-time.After(5 * time.Second):
+			t.Fatal("call with an expired context waited for the first chain ID lookup")
+		}
+
+		unblock()
+		assert.NoError(t, <-first)
+	})
+
+	t.Run("node on another chain", func(t *testing.T) {
+		var sends atomic.Int32
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_chainId": result("0xa5b8"),
+			"eth_sendRawTransaction": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+				sends.Add(1)
+				return "0x", nil
+			},
+		})
+		c := New(server.URL, WithChain(chains.Testnet))
+
+		_, err := c.GetBlockNumber(ctx)
+		assert.ErrorIs(t, err, ErrChainIDMismatch)
+		_, err = c.SendRawTransaction(ctx, testnetTx)
+		assert.ErrorIs(t, err, ErrChainIDMismatch)
+		assert.Zero(t, sends.Load())
+
+		// eth_chainId itself still reaches the node.
+		chainID, err := c.ChainID(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(transaction.ChainIDTempo), chainID)
+	})
+
+	t.Run("raw transaction for another chain", func(t *testing.T) {
+		var sends atomic.Int32
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_chainId": result("0xa5bd"),
+			"eth_sendRawTransaction": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+				sends.Add(1)
+				return "0x01", nil
+			},
+		})
+		c := New(server.URL, WithChainID(big.NewInt(transaction.ChainIDTempoTestnet)))
+
+		_, err := c.SendRawTransaction(ctx, mainnetTx)
+		assert.ErrorIs(t, err, ErrChainIDMismatch)
+		assert.Zero(t, sends.Load())
+
+		_, err = c.SendRawTransaction(ctx, testnetTx)
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), sends.Load())
+	})
+
+	t.Run("ethereum transactions", func(t *testing.T) {
+		legacy := func(v int64) string {
+			data, err := rlp.EncodeToBytes([]interface{}{
+				uint64(0), big.NewInt(1), uint64(21000), common.Address{}, big.NewInt(0), []byte{},
+				big.NewInt(v), big.NewInt(1), big.NewInt(1),
+			})
+			require.NoError(t, err)
+			return hexutil.Encode(data)
+		}
+		dynamicFee := func(chainID int64) string {
+			data, err := rlp.EncodeToBytes([]interface{}{
+				big.NewInt(chainID), uint64(0), big.NewInt(1), big.NewInt(1), uint64(21000),
+				common.Address{}, big.NewInt(0), []byte{}, []interface{}{},
+				uint64(0), big.NewInt(1), big.NewInt(1),
+			})
+			require.NoError(t, err)
+			return hexutil.Encode(append([]byte{0x02}, data...))
+		}
+
+		tests := []struct {
+			name     string
+			tx       string
+			mismatch bool
+		}{
+			{"legacy", legacy(transaction.ChainIDTempoTestnet*2 + 35), false},
+			{"legacy for another chain", legacy(transaction.ChainIDTempo*2 + 36), true},
+			{"legacy without chain ID", legacy(27), false},
+			{"EIP-1559", dynamicFee(transaction.ChainIDTempoTestnet), false},
+			{"EIP-1559 for another chain", dynamicFee(transaction.ChainIDTempo), true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var sends atomic.Int32
+				server := newRPCServer(t, map[string]rpcHandler{
+					"eth_chainId": result("0xa5bd"),
+					"eth_sendRawTransaction": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+						sends.Add(1)
+						return "0x01", nil
+					},
+				})
+				c := New(server.URL, WithChain(chains.Testnet))
+
+				_, err := c.SendRawTransaction(ctx, tt.tx)
+				if tt.mismatch {
+					assert.ErrorIs(t, err, ErrChainIDMismatch)
+					assert.Zero(t, sends.Load())
+				} else {
+					assert.NoError(t, err)
+					assert.Equal(t, int32(1), sends.Load())
+				}
+			})
+		}
+	})
+
+	t.Run("node unreachable", func(t *testing.T) {
+		_, err := New("http://127.0.0.1:0", WithChain(chains.Testnet)).GetBlockNumber(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to verify chain ID")
+		assert.NotErrorIs(t, err, ErrChainIDMismatch)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_sendRawTransaction": result("0x01"),
+		})
+
+		_, err := New(server.URL).SendRawTransaction(ctx, mainnetTx)
+		assert.NoError(t, err)
+	})
+}
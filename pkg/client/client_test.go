@@ -3,14 +3,80 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempoxyz/tempo-go/pkg/signer"
+	"github.com/tempoxyz/tempo-go/pkg/transaction"
 )
 
+// test private key pulled from `anvil` using default seed -- please don't use this in production!
+const testPrivateKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+// rpcHandler returns the result (or error) for a single JSON-RPC request.
+type rpcHandler func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError)
+
+// newRPCServer starts a test server that dispatches single JSON-RPC requests by method.
+// Requests for methods without a handler fail the test.
+func newRPCServer(t *testing.T, handlers map[string]rpcHandler) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req JSONRPCRequest
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&req)) {
+			return
+		}
+
+		handler, ok := handlers[req.Method]
+		if !ok {
+			t.Errorf("unexpected method %s", req.Method)
+			return
+		}
+
+		result, rpcErr := handler(t, req)
+		resp := NewJSONRPCResponse(req.ID, result)
+		if rpcErr != nil {
+			resp = NewJSONRPCErrorResponse(req.ID, rpcErr.Code, rpcErr.Message, rpcErr.Data)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// result returns an rpcHandler that always responds with v.
+func result(v interface{}) rpcHandler {
+	return func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+		return v, nil
+	}
+}
+
+// newSignedTx returns a transaction signed with testPrivateKey for the given chain ID.
+func newSignedTx(t *testing.T, chainID int64) *transaction.Tx {
+	t.Helper()
+	sgn, err := signer.NewSigner(testPrivateKey)
+	require.NoError(t, err)
+
+	tx := transaction.NewBuilder(big.NewInt(chainID)).
+		SetGas(100000).
+		SetMaxFeePerGas(big.NewInt(2000000000)).
+		SetMaxPriorityFeePerGas(big.NewInt(1000000000)).
+		AddCall(common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"), big.NewInt(0), nil).
+		Build()
+	require.NoError(t, transaction.SignTransaction(tx, sgn))
+	return tx
+}
+
 func TestSendRawTransaction(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, http.MethodPost, r.Method)
@@ -549,6 +615,152 @@ func TestSendBatch_EdgeCases(t *testing.T) {
 	})
 }
 
+func TestResponseIDMatching(t *testing.T) {
+	ctx := context.Background()
+
+	// newServer answers every batch with the responses returned by respond.
+	newServer := func(t *testing.T, respond func(reqs []JSONRPCRequest) []*JSONRPCResponse) *Client {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var reqs []JSONRPCRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&reqs))
+			json.NewEncoder(w).Encode(respond(reqs))
+		}))
+		t.Cleanup(server.Close)
+		return New(server.URL)
+	}
+	batch := func() *BatchRequest {
+		return NewBatchRequest().Add("eth_blockNumber").Add("eth_chainId").Add("eth_gasPrice")
+	}
+
+	t.Run("unique request IDs", func(t *testing.T) {
+		var mu sync.Mutex
+		seen := make(map[string]bool)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req JSONRPCRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			mu.Lock()
+			assert.False(t, seen[fmt.Sprint(req.ID)], "ID %v reused", req.ID)
+			seen[fmt.Sprint(req.ID)] = true
+			mu.Unlock()
+			json.NewEncoder(w).Encode(NewJSONRPCResponse(req.ID, "0x1"))
+		}))
+		defer server.Close()
+
+		client := New(server.URL)
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := client.SendRequest(ctx, "eth_blockNumber")
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+		_, err := client.SendRawTransaction(ctx, "0x76")
+		require.NoError(t, err)
+		_, err = client.SignTransaction(ctx, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Len(t, seen, 12)
+	})
+
+	t.Run("single response with another ID", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(NewJSONRPCResponse(12345, "0x1"))
+		}))
+		defer server.Close()
+
+		_, err := New(server.URL).SendRequest(ctx, "eth_blockNumber")
+		var idErr *ResponseIDError
+		require.ErrorAs(t, err, &idErr)
+		assert.ErrorIs(t, err, ErrUnexpectedResponse)
+		assert.Equal(t, float64(12345), idErr.ID)
+	})
+
+	t.Run("single error response with null ID", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(NewJSONRPCErrorResponse(nil, ParseError, "parse error", nil))
+		}))
+		defer server.Close()
+
+		response, err := New(server.URL).SendRequest(ctx, "eth_blockNumber")
+		require.NoError(t, err)
+		assert.Equal(t, ParseError, response.Error.Code)
+	})
+
+	t.Run("reordered batch", func(t *testing.T) {
+		client := newServer(t, func(reqs []JSONRPCRequest) []*JSONRPCResponse {
+			responses := make([]*JSONRPCResponse, len(reqs))
+			for i, req := range reqs {
+				responses[len(reqs)-1-i] = NewJSONRPCResponse(req.ID, req.Method)
+			}
+			return responses
+		})
+
+		responses, err := client.SendBatch(ctx, batch())
+		require.NoError(t, err)
+		require.Len(t, responses, 3)
+		for i, method := range []string{"eth_blockNumber", "eth_chainId", "eth_gasPrice"} {
+			assert.Equal(t, method, responses[i].Result)
+			assert.Equal(t, i+1, responses[i].ID)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		respond func(reqs []JSONRPCRequest) []*JSONRPCResponse
+		want    error
+		id      interface{}
+		method  string
+	}{
+		{
+			name: "missing response",
+			respond: func(reqs []JSONRPCRequest) []*JSONRPCResponse {
+				return []*JSONRPCResponse{NewJSONRPCResponse(reqs[0].ID, "0x1"), NewJSONRPCResponse(reqs[2].ID, "0x1")}
+			},
+			want:   ErrMissingResponse,
+			id:     2,
+			method: "eth_chainId",
+		},
+		{
+			name: "duplicate response",
+			respond: func(reqs []JSONRPCRequest) []*JSONRPCResponse {
+				return []*JSONRPCResponse{
+					NewJSONRPCResponse(reqs[0].ID, "0x1"),
+					NewJSONRPCResponse(reqs[2].ID, "0x1"),
+					NewJSONRPCResponse(reqs[2].ID, "0x2"),
+				}
+			},
+			want:   ErrDuplicateResponse,
+			id:     3,
+			method: "eth_gasPrice",
+		},
+		{
+			name: "unexpected response",
+			respond: func(reqs []JSONRPCRequest) []*JSONRPCResponse {
+				return []*JSONRPCResponse{
+					NewJSONRPCResponse(reqs[0].ID, "0x1"),
+					NewJSONRPCResponse(reqs[1].ID, "0x1"),
+					NewJSONRPCResponse(7, "0x1"),
+				}
+			},
+			want: ErrUnexpectedResponse,
+			id:   float64(7),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newServer(t, tt.respond).SendBatch(ctx, batch())
+			assert.ErrorIs(t, err, tt.want)
+
+			var idErr *ResponseIDError
+			require.ErrorAs(t, err, &idErr)
+			assert.Equal(t, tt.id, idErr.ID)
+			assert.Equal(t, tt.method, idErr.Method)
+		})
+	}
+}
+
 func TestSendRequest_HTTPError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusServiceUnavailable)
@@ -563,3 +775,80 @@ func TestSendRequest_HTTPError(t *testing.T) {
 	assert.Contains(t, err.Error(), "HTTP error 503")
 	assert.Contains(t, err.Error(), "Service Unavailable")
 }
+
+func TestSendTransaction(t *testing.T) {
+	tx := newSignedTx(t, transaction.ChainIDTempoTestnet)
+	expectedHash, err := tx.Hash()
+	require.NoError(t, err)
+	serialized, err := transaction.Serialize(tx, nil)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		chainIDCalls := 0
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_chainId": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+				chainIDCalls++
+				return "0xa5bd", nil
+			},
+			"eth_sendRawTransaction": func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError) {
+				assert.Equal(t, serialized, req.Params[0])
+				return expectedHash.Hex(), nil
+			},
+		})
+
+		client := New(server.URL)
+		hash, err := client.SendTransaction(context.Background(), tx)
+		require.NoError(t, err)
+		assert.Equal(t, expectedHash, hash)
+
+		// The chain ID is only fetched once.
+		_, err = client.SendTransaction(context.Background(), tx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, chainIDCalls)
+	})
+
+	t.Run("sync", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_chainId":                result("0xa5bd"),
+			"eth_sendRawTransactionSync": result(expectedHash.Hex()),
+		})
+
+		hash, err := New(server.URL).SendTransactionSync(context.Background(), tx)
+		require.NoError(t, err)
+		assert.Equal(t, expectedHash, hash)
+	})
+
+	t.Run("chain ID mismatch", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_chainId": result("0xa5b8"),
+		})
+
+		_, err := New(server.URL).SendTransaction(context.Background(), tx)
+		assert.True(t, errors.Is(err, ErrChainIDMismatch))
+	})
+
+	t.Run("hash mismatch", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_chainId":            result("0xa5bd"),
+			"eth_sendRawTransaction": result(common.HexToHash("0x01").Hex()),
+		})
+
+		_, err := New(server.URL).SendTransaction(context.Background(), tx)
+		assert.True(t, errors.Is(err, ErrTxHashMismatch))
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		unsigned := tx.Clone()
+
+		_, err := New("http://localhost:8545").SendTransaction(context.Background(), unsigned)
+		assert.True(t, errors.Is(err, transaction.ErrNoSignature))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		invalid := tx.Clone()
+		invalid.Gas = 0
+
+		_, err := New("http://localhost:8545").SendTransaction(context.Background(), invalid)
+		assert.True(t, errors.Is(err, transaction.ErrInvalidTransaction))
+	})
+}
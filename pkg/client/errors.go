@@ -0,0 +1,221 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sentinel errors for common error conditions.
+// Use errors.Is() to check for specific error types.
+var (
+	// ErrChainIDMismatch is returned when a transaction's chain ID differs from the node's chain ID.
+	ErrChainIDMismatch = errors.New("chain ID mismatch")
+
+	// ErrTxHashMismatch is returned when the node reports a different hash than the one computed locally.
+	ErrTxHashMismatch = errors.New("transaction hash mismatch")
+
+	// ErrNotFound is returned when the node has no data for the requested object,
+	// such as a receipt for a transaction that has not been included yet.
+	ErrNotFound = errors.New("not found")
+
+	// ErrMissingResponse is returned when the node does not answer a request, such as
+	// an item of a batch. The error is a *ResponseIDError naming the request.
+	ErrMissingResponse = errors.New("missing response")
+
+	// ErrDuplicateResponse is returned when the node answers a request more than once.
+	ErrDuplicateResponse = errors.New("duplicate response")
+
+	// ErrResponseTooLarge is returned when a response exceeds the limit set with
+	// WithMaxResponseSize.
+	ErrResponseTooLarge = errors.New("response too large")
+
+	// ErrUnexpectedResponse is returned when a response carries an ID that matches none
+	// of the requests sent.
+	ErrUnexpectedResponse = errors.New("unexpected response")
+)
+
+// Sentinel errors for node rejections. JSONRPCError and HTTPError values match these
+// with errors.Is, based on the error code and message reported by the node.
+var (
+	// ErrNonceTooLow is returned when the nonce has already been used for the nonce key.
+	ErrNonceTooLow = errors.New("nonce too low")
+
+	// ErrNonceTooHigh is returned when the nonce is ahead of the next nonce for the nonce key.
+	ErrNonceTooHigh = errors.New("nonce too high")
+
+	// ErrInsufficientFeeTokenBalance is returned when the fee payer cannot cover the
+	// maximum fee in the fee token.
+	ErrInsufficientFeeTokenBalance = errors.New("insufficient fee token balance")
+
+	// ErrUnsupportedFeeToken is returned when the fee token cannot be used to pay fees.
+	ErrUnsupportedFeeToken = errors.New("unsupported fee token")
+
+	// ErrTxExpired is returned when the transaction's ValidBefore has passed.
+	ErrTxExpired = errors.New("transaction expired")
+
+	// ErrTxNotYetValid is returned when the transaction's ValidAfter has not been reached.
+	ErrTxNotYetValid = errors.New("transaction not yet valid")
+
+	// ErrAlreadyKnown is returned when the node already has the transaction in its pool.
+	ErrAlreadyKnown = errors.New("transaction already known")
+
+	// ErrReplacementUnderpriced is returned when a replacement for a pending transaction
+	// does not raise its fees enough.
+	ErrReplacementUnderpriced = errors.New("replacement transaction underpriced")
+
+	// ErrExecutionReverted is returned when execution reverts. Call and EstimateGas
+	// return a *RevertError carrying the revert data.
+	ErrExecutionReverted = errors.New("execution reverted")
+
+	// ErrRateLimited is returned when the node rejects a request for exceeding its rate
+	// limit. A *HTTPError carries the Retry-After delay when the node sends one.
+	ErrRateLimited = errors.New("rate limited")
+)
+
+const (
+	// revertErrorCode is the JSON-RPC error code nodes use for reverted execution.
+	revertErrorCode = 3
+
+	// limitExceededErrorCode is the JSON-RPC error code for exceeded request limits (EIP-1474).
+	limitExceededErrorCode = -32005
+)
+
+// rpcErrorClass maps node error codes and messages to a sentinel error.
+type rpcErrorClass struct {
+	err      error
+	codes    []int
+	messages []string
+}
+
+// rpcErrorClasses is the single place where node errors are classified. Messages are
+// matched case-insensitively as substrings, in order, so more specific classes come
+// first. They name the transaction or its fields, so that errors from auth proxies,
+// such as "jwt expired", do not read as a rejected transaction. Reverts are recognized before any class, since a revert reason can read like
+// any other error.
+var rpcErrorClasses = []rpcErrorClass{
+	{err: ErrNonceTooLow, messages: []string{"nonce too low"}},
+	{err: ErrNonceTooHigh, messages: []string{"nonce too high"}},
+	{err: ErrAlreadyKnown, messages: []string{"already known", "known transaction", "already imported"}},
+	{err: ErrReplacementUnderpriced, messages: []string{"replacement transaction underpriced", "replacement underpriced"}},
+	{err: ErrUnsupportedFeeToken, messages: []string{"unsupported fee token", "invalid fee token", "fee token not supported", "fee token is not supported"}},
+	{err: ErrInsufficientFeeTokenBalance, messages: []string{"insufficient fee token", "insufficient funds for gas"}},
+	{err: ErrTxNotYetValid, messages: []string{"transaction not yet valid", "valid_after", "validafter"}},
+	{err: ErrTxExpired, messages: []string{"transaction expired", "transaction has expired", "valid_before", "validbefore"}},
+	{err: ErrExecutionReverted, codes: []int{revertErrorCode}, messages: []string{"revert"}},
+	{err: ErrRateLimited, codes: []int{limitExceededErrorCode}, messages: []string{"rate limit", "too many requests"}},
+}
+
+// classifyRPCError returns the sentinel error matching a JSON-RPC error code and
+// message, or nil if none matches.
+func classifyRPCError(code int, message string) error {
+	message = strings.ToLower(message)
+	if code == revertErrorCode || strings.HasPrefix(message, "execution reverted") {
+		return ErrExecutionReverted
+	}
+	for _, class := range rpcErrorClasses {
+		for _, m := range class.messages {
+			if strings.Contains(message, m) {
+				return class.err
+			}
+		}
+	}
+	for _, class := range rpcErrorClasses {
+		for _, c := range class.codes {
+			if code == c {
+				return class.err
+			}
+		}
+	}
+	return nil
+}
+
+// Classify returns the sentinel error that err matches, such as ErrNonceTooLow or
+// ErrRateLimited, or nil if err is not a recognized node rejection.
+func Classify(err error) error {
+	var rpcErr *JSONRPCError
+	if errors.As(err, &rpcErr) {
+		return classifyRPCError(rpcErr.Code, rpcErr.Message)
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests {
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// HTTPError is returned when the node responds with a non-200 HTTP status.
+type HTTPError struct {
+	StatusCode int
+
+	// Body holds the start of the response body, truncated to 1 KiB.
+	Body string
+
+	// RetryAfter is the delay requested by the Retry-After header, or zero.
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface for HTTPError.
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("HTTP error %d: %s", e.StatusCode, e.Body)
+}
+
+// Is reports whether the HTTP error matches target. HTTP 429 matches ErrRateLimited.
+func (e *HTTPError) Is(target error) bool {
+	return target == ErrRateLimited && e.StatusCode == http.StatusTooManyRequests
+}
+
+// ResponseIDError is returned when the responses to a call do not match its requests
+// one to one by ID. Err is ErrMissingResponse, ErrDuplicateResponse or
+// ErrUnexpectedResponse.
+type ResponseIDError struct {
+	Err error
+
+	// ID is the ID of the request, or of the response for ErrUnexpectedResponse.
+	ID interface{}
+
+	// Method is the method of the request, or empty for ErrUnexpectedResponse.
+	Method string
+}
+
+// Error implements the error interface for ResponseIDError.
+func (e *ResponseIDError) Error() string {
+	if e.Method == "" {
+		return fmt.Sprintf("%v with ID %v", e.Err, e.ID)
+	}
+	return fmt.Sprintf("%v for request %v (%s)", e.Err, e.ID, e.Method)
+}
+
+// Unwrap returns the sentinel error.
+func (e *ResponseIDError) Unwrap() error {
+	return e.Err
+}
+
+// newHTTPError creates an HTTPError from a non-200 response and its body.
+func newHTTPError(resp *http.Response, body []byte) *HTTPError {
+	return &HTTPError{
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONRPCErrorClassification(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *JSONRPCError
+		want error
+	}{
+		{name: "nonce too low", err: &JSONRPCError{Code: -32000, Message: "nonce too low: next nonce 5, tx nonce 3"}, want: ErrNonceTooLow},
+		{name: "nonce too high", err: &JSONRPCError{Code: -32000, Message: "Nonce too high"}, want: ErrNonceTooHigh},
+		{name: "insufficient funds", err: &JSONRPCError{Code: -32000, Message: "insufficient funds for gas * price + value"}, want: ErrInsufficientFeeTokenBalance},
+		{name: "insufficient fee token", err: &JSONRPCError{Code: -32003, Message: "Insufficient fee token balance"}, want: ErrInsufficientFeeTokenBalance},
+		{name: "unsupported fee token", err: &JSONRPCError{Code: -32000, Message: "invalid fee token: 0x20c0...0009"}, want: ErrUnsupportedFeeToken},
+		{name: "expired", err: &JSONRPCError{Code: -32000, Message: "transaction expired: valid_before 1700000000"}, want: ErrTxExpired},
+		{name: "not yet valid", err: &JSONRPCError{Code: -32000, Message: "transaction not yet valid"}, want: ErrTxNotYetValid},
+		{name: "already known", err: &JSONRPCError{Code: -32000, Message: "already known"}, want: ErrAlreadyKnown},
+		{name: "underpriced replacement", err: &JSONRPCError{Code: -32000, Message: "replacement transaction underpriced"}, want: ErrReplacementUnderpriced},
+		{name: "revert code", err: &JSONRPCError{Code: 3, Message: "custom error"}, want: ErrExecutionReverted},
+		{name: "revert message", err: &JSONRPCError{Code: -32000, Message: "execution reverted"}, want: ErrExecutionReverted},
+		{name: "revert reason like a balance error", err: &JSONRPCError{Code: 3, Message: "execution reverted: insufficient balance"}, want: ErrExecutionReverted},
+		{name: "revert reason like an expiry", err: &JSONRPCError{Code: 3, Message: "execution reverted: permit expired"}, want: ErrExecutionReverted},
+		{name: "revert reason like a nonce error", err: &JSONRPCError{Code: -32000, Message: "execution reverted: nonce too low"}, want: ErrExecutionReverted},
+		{name: "expired auth token", err: &JSONRPCError{Code: -32000, Message: "jwt expired"}, want: nil},
+		{name: "auth token not yet valid", err: &JSONRPCError{Code: -32000, Message: "token is not yet valid, not valid before 1700000000"}, want: nil},
+		{name: "insufficient balance", err: &JSONRPCError{Code: -32000, Message: "insufficient balance for transfer"}, want: nil},
+		{name: "limit exceeded", err: &JSONRPCError{Code: -32005, Message: "request limit reached"}, want: ErrRateLimited},
+		{name: "unclassified", err: &JSONRPCError{Code: MethodNotFound, Message: "method not found"}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapped := fmt.Errorf("eth_sendRawTransaction: %w", tt.err)
+			assert.Equal(t, tt.want, Classify(wrapped))
+			if tt.want != nil {
+				assert.True(t, errors.Is(wrapped, tt.want))
+			}
+			// Each error matches only its own class.
+			assert.False(t, errors.Is(wrapped, ErrChainIDMismatch))
+			if tt.want != ErrNonceTooLow {
+				assert.False(t, errors.Is(wrapped, ErrNonceTooLow))
+			}
+
+			// The original error stays reachable.
+			var rpcErr *JSONRPCError
+			require.True(t, errors.As(wrapped, &rpcErr))
+			assert.Equal(t, tt.err.Code, rpcErr.Code)
+		})
+	}
+}
+
+func TestHTTPError(t *testing.T) {
+	t.Run("rate limited", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte("slow down"))
+		}))
+		defer server.Close()
+
+		_, err := New(server.URL).GetBlockNumber(context.Background())
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrRateLimited))
+		assert.Equal(t, ErrRateLimited, Classify(err))
+		assert.Equal(t, "HTTP error 429: slow down", err.Error())
+
+		var httpErr *HTTPError
+		require.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, 7*time.Second, httpErr.RetryAfter)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		_, err := New(server.URL).SendBatch(context.Background(), NewBatchRequest().Add("eth_blockNumber"))
+		require.Error(t, err)
+		assert.False(t, errors.Is(err, ErrRateLimited))
+
+		var httpErr *HTTPError
+		require.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, http.StatusBadGateway, httpErr.StatusCode)
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+	assert.Equal(t, 30*time.Second, parseRetryAfter("30", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("-1", now))
+	assert.Equal(t, 90*time.Second, parseRetryAfter("Wed, 01 Jan 2025 00:01:30 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("Tue, 31 Dec 2024 23:59:00 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+}
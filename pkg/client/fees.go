@@ -0,0 +1,395 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/tempoxyz/tempo-go/pkg/transaction"
+)
+
+const (
+	defaultFeeHistoryBlocks  = 20
+	defaultBaseFeeMultiplier = 2
+)
+
+// defaultFeePercentiles are the reward percentiles used for slow, normal and fast fees.
+var defaultFeePercentiles = [3]float64{10, 50, 90}
+
+// FeeHistory is the fee history of a range of blocks, as returned by eth_feeHistory.
+type FeeHistory struct {
+	// OldestBlock is the number of the first block in the range.
+	OldestBlock uint64
+
+	// BaseFeePerGas holds the base fee of each block in the range, followed by the
+	// base fee of the next block.
+	BaseFeePerGas []*big.Int
+
+	// GasUsedRatio holds the fraction of the gas limit used by each block.
+	GasUsedRatio []float64
+
+	// Reward holds, for each block, the priority fee at each requested percentile.
+	Reward [][]*big.Int
+}
+
+type feeHistoryJSON struct {
+	OldestBlock   hexutil.Uint64   `json:"oldestBlock"`
+	BaseFeePerGas []*hexutil.Big   `json:"baseFeePerGas"`
+	GasUsedRatio  []float64        `json:"gasUsedRatio"`
+	Reward        [][]*hexutil.Big `json:"reward"`
+}
+
+// UnmarshalJSON decodes a fee history from its JSON-RPC representation.
+func (h *FeeHistory) UnmarshalJSON(data []byte) error {
+	var dec feeHistoryJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+
+	*h = FeeHistory{
+		OldestBlock:   uint64(dec.OldestBlock),
+		BaseFeePerGas: make([]*big.Int, len(dec.BaseFeePerGas)),
+		GasUsedRatio:  dec.GasUsedRatio,
+		Reward:        make([][]*big.Int, len(dec.Reward)),
+	}
+	for i, fee := range dec.BaseFeePerGas {
+		h.BaseFeePerGas[i] = (*big.Int)(fee)
+	}
+	for i, rewards := range dec.Reward {
+		h.Reward[i] = make([]*big.Int, len(rewards))
+		for j, reward := range rewards {
+			h.Reward[i][j] = (*big.Int)(reward)
+		}
+	}
+	return nil
+}
+
+// GasPrice gets the node's suggested gas price.
+func (c *Client) GasPrice(ctx context.Context) (*big.Int, error) {
+	var price hexutil.Big
+	if err := c.call(ctx, &price, "eth_gasPrice"); err != nil {
+		return nil, err
+	}
+	return price.ToInt(), nil
+}
+
+// MaxPriorityFeePerGas gets the node's suggested priority fee per gas.
+func (c *Client) MaxPriorityFeePerGas(ctx context.Context) (*big.Int, error) {
+	var tip hexutil.Big
+	if err := c.call(ctx, &tip, "eth_maxPriorityFeePerGas"); err != nil {
+		return nil, err
+	}
+	return tip.ToInt(), nil
+}
+
+// FeeHistory gets the fee history of blockCount blocks ending at newest, with the
+// priority fees paid at the given reward percentiles (0-100, ascending).
+func (c *Client) FeeHistory(ctx context.Context, blockCount uint64, newest BlockSelector, percentiles []float64) (*FeeHistory, error) {
+	if percentiles == nil {
+		percentiles = []float64{}
+	}
+
+	var history *FeeHistory
+	if err := c.call(ctx, &history, "eth_feeHistory", hexutil.Uint64(blockCount), newest, percentiles); err != nil {
+		return nil, err
+	}
+	if history == nil {
+		return nil, fmt.Errorf("%w: fee history", ErrNotFound)
+	}
+	return history, nil
+}
+
+// FeeSpeed selects how aggressively a fee suggestion bids for inclusion.
+type FeeSpeed int
+
+const (
+	// FeeSlow bids a low priority fee and may wait several blocks under load.
+	FeeSlow FeeSpeed = iota
+
+	// FeeNormal bids the typical priority fee paid in recent blocks.
+	FeeNormal
+
+	// FeeFast bids a high priority fee for inclusion in the next block.
+	FeeFast
+)
+
+// String returns the name of the fee speed.
+func (s FeeSpeed) String() string {
+	switch s {
+	case FeeSlow:
+		return "slow"
+	case FeeNormal:
+		return "normal"
+	case FeeFast:
+		return "fast"
+	default:
+		return fmt.Sprintf("FeeSpeed(%d)", int(s))
+	}
+}
+
+// FeeEstimate is a suggested pair of EIP-1559 fee parameters.
+type FeeEstimate struct {
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+}
+
+// MaxCost returns the most a transaction with the given gas limit can cost at this
+// estimate, in fee token units rounded up.
+func (e FeeEstimate) MaxCost(gas uint64) *big.Int {
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(gas), e.MaxFeePerGas)
+	cost.Add(cost, new(big.Int).Sub(feeTokenScale, big.NewInt(1)))
+	return cost.Div(cost, feeTokenScale)
+}
+
+// Apply sets the fee parameters of a transaction builder to this estimate.
+func (e FeeEstimate) Apply(b *transaction.Builder) *transaction.Builder {
+	return b.
+		SetMaxFeePerGas(new(big.Int).Set(e.MaxFeePerGas)).
+		SetMaxPriorityFeePerGas(new(big.Int).Set(e.MaxPriorityFeePerGas))
+}
+
+func (e FeeEstimate) copy() FeeEstimate {
+	return FeeEstimate{
+		MaxFeePerGas:         copyBig(e.MaxFeePerGas),
+		MaxPriorityFeePerGas: copyBig(e.MaxPriorityFeePerGas),
+	}
+}
+
+// FeeSuggestions holds slow, normal and fast fee estimates computed at a block.
+type FeeSuggestions struct {
+	// BlockNumber is the block the suggestions were computed at.
+	BlockNumber uint64
+
+	// BaseFee is the base fee expected for the next block.
+	BaseFee *big.Int
+
+	// GasPrice is the node's suggested gas price, used as a floor for MaxFeePerGas.
+	GasPrice *big.Int
+
+	Slow   FeeEstimate
+	Normal FeeEstimate
+	Fast   FeeEstimate
+}
+
+// copy returns a deep copy, so callers can modify suggestions without affecting the
+// oracle's cache.
+func (s *FeeSuggestions) copy() *FeeSuggestions {
+	return &FeeSuggestions{
+		BlockNumber: s.BlockNumber,
+		BaseFee:     copyBig(s.BaseFee),
+		GasPrice:    copyBig(s.GasPrice),
+		Slow:        s.Slow.copy(),
+		Normal:      s.Normal.copy(),
+		Fast:        s.Fast.copy(),
+	}
+}
+
+// Get returns the estimate for the given speed.
+func (s *FeeSuggestions) Get(speed FeeSpeed) FeeEstimate {
+	switch speed {
+	case FeeSlow:
+		return s.Slow
+	case FeeFast:
+		return s.Fast
+	default:
+		return s.Normal
+	}
+}
+
+// FeeQuote is the fee applied to a transaction and the most the transaction can cost.
+type FeeQuote struct {
+	FeeEstimate
+
+	// FeeToken is the token the fee is paid in. The zero address means the
+	// protocol picks the sender's default fee token.
+	FeeToken common.Address
+
+	// Gas is the gas limit the cost was computed for.
+	Gas uint64
+
+	// MaxCost is the maximum fee in FeeToken units.
+	MaxCost *big.Int
+}
+
+// FeeOracleOptions configures a FeeOracle.
+type FeeOracleOptions struct {
+	// BlockCount is the number of recent blocks to sample. Default: 20.
+	BlockCount uint64
+
+	// Percentiles are the reward percentiles used for slow, normal and fast fees.
+	// Default: 10, 50 and 90.
+	Percentiles [3]float64
+
+	// BaseFeeMultiplier scales the next base fee in MaxFeePerGas, leaving headroom
+	// for base fee increases while the transaction is pending. Default: 2.
+	BaseFeeMultiplier int64
+}
+
+// FeeOracle suggests fees from the node's gas price, priority fee and recent fee
+// history. Suggestions are cached until a new block is produced.
+// FeeOracle is safe for concurrent use.
+type FeeOracle struct {
+	client *Client
+	opts   FeeOracleOptions
+
+	mu     sync.Mutex
+	cached *FeeSuggestions
+}
+
+// NewFeeOracle creates a fee oracle backed by the given client.
+// If opts is nil, default options are used.
+func NewFeeOracle(c *Client, opts *FeeOracleOptions) *FeeOracle {
+	o := &FeeOracle{client: c}
+	if opts != nil {
+		o.opts = *opts
+	}
+	if o.opts.BlockCount == 0 {
+		o.opts.BlockCount = defaultFeeHistoryBlocks
+	}
+	if o.opts.Percentiles == [3]float64{} {
+		o.opts.Percentiles = defaultFeePercentiles
+	}
+	if o.opts.BaseFeeMultiplier <= 0 {
+		o.opts.BaseFeeMultiplier = defaultBaseFeeMultiplier
+	}
+	return o
+}
+
+// Suggest returns slow, normal and fast fee estimates for the latest block.
+// Results are reused until the chain advances past the block they were computed at;
+// each call returns a copy the caller may modify.
+func (o *FeeOracle) Suggest(ctx context.Context) (*FeeSuggestions, error) {
+	blockNumber, err := o.client.GetBlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block number: %w", err)
+	}
+
+	o.mu.Lock()
+	cached := o.cached
+	o.mu.Unlock()
+	if cached != nil && cached.BlockNumber == blockNumber {
+		return cached.copy(), nil
+	}
+
+	suggestions, err := o.suggest(ctx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	o.mu.Lock()
+	if o.cached == nil || o.cached.BlockNumber <= blockNumber {
+		o.cached = suggestions
+	}
+	o.mu.Unlock()
+	return suggestions.copy(), nil
+}
+
+// Populate sets the fee parameters of a transaction builder for the given speed and
+// returns the applied fee along with the maximum cost at the builder's gas limit.
+// Set the gas limit (for example with AutoGas) before populating fees.
+func (o *FeeOracle) Populate(ctx context.Context, b *transaction.Builder, speed FeeSpeed) (*FeeQuote, error) {
+	suggestions, err := o.Suggest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate := suggestions.Get(speed)
+	tx := estimate.Apply(b).Build()
+	return &FeeQuote{
+		FeeEstimate: estimate,
+		FeeToken:    tx.FeeToken,
+		Gas:         tx.Gas,
+		MaxCost:     estimate.MaxCost(tx.Gas),
+	}, nil
+}
+
+func (o *FeeOracle) suggest(ctx context.Context, blockNumber uint64) (*FeeSuggestions, error) {
+	history, err := o.client.FeeHistory(ctx, o.opts.BlockCount, AtBlockNumber(blockNumber), o.opts.Percentiles[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee history: %w", err)
+	}
+	if len(history.BaseFeePerGas) == 0 {
+		return nil, fmt.Errorf("fee history for block %d has no base fees", blockNumber)
+	}
+	nodeTip, err := o.client.MaxPriorityFeePerGas(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get max priority fee: %w", err)
+	}
+	gasPrice, err := o.client.GasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+
+	baseFee := history.BaseFeePerGas[len(history.BaseFeePerGas)-1]
+	var tips [3]*big.Int
+	for i := range tips {
+		tips[i] = medianReward(history, i)
+		if tips[i] == nil {
+			tips[i] = nodeTip
+		}
+	}
+	// The node's own suggestion is the floor for normal and fast fees, and each
+	// speed bids at least as much as the one below it.
+	for i := FeeNormal; i <= FeeFast; i++ {
+		tips[i] = bigMax(tips[i], tips[i-1])
+		tips[i] = bigMax(tips[i], nodeTip)
+	}
+
+	var estimates [3]FeeEstimate
+	for i, tip := range tips {
+		maxFee := new(big.Int).Mul(baseFee, big.NewInt(o.opts.BaseFeeMultiplier))
+		maxFee.Add(maxFee, tip)
+		estimates[i] = FeeEstimate{
+			MaxFeePerGas:         bigMax(maxFee, gasPrice),
+			MaxPriorityFeePerGas: new(big.Int).Set(tip),
+		}
+	}
+
+	return &FeeSuggestions{
+		BlockNumber: blockNumber,
+		BaseFee:     baseFee,
+		GasPrice:    gasPrice,
+		Slow:        estimates[FeeSlow],
+		Normal:      estimates[FeeNormal],
+		Fast:        estimates[FeeFast],
+	}, nil
+}
+
+// medianReward returns the median reward at the given percentile index across the
+// sampled blocks, skipping empty blocks. Returns nil if no block has rewards.
+func medianReward(history *FeeHistory, index int) *big.Int {
+	var samples []*big.Int
+	for i, rewards := range history.Reward {
+		if i < len(history.GasUsedRatio) && history.GasUsedRatio[i] == 0 {
+			continue
+		}
+		if index < len(rewards) && rewards[index] != nil {
+			samples = append(samples, rewards[index])
+		}
+	}
+	if len(samples) == 0 {
+		return nil
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].Cmp(samples[j]) < 0 })
+	return samples[len(samples)/2]
+}
+
+// copyBig returns a copy of x, or nil if x is nil.
+func copyBig(x *big.Int) *big.Int {
+	if x == nil {
+		return nil
+	}
+	return new(big.Int).Set(x)
+}
+
+func bigMax(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempoxyz/tempo-go/pkg/transaction"
+)
+
+func gwei(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1_000_000_000))
+}
+
+// newFeeServer serves a fixed fee history and counts eth_feeHistory calls.
+func newFeeServer(t *testing.T, blockNumber *atomic.Int64, feeHistoryCalls *atomic.Int32) *Client {
+	t.Helper()
+	rewards := func(a, b, c int64) []interface{} {
+		return []interface{}{hexutil.EncodeBig(gwei(a)), hexutil.EncodeBig(gwei(b)), hexutil.EncodeBig(gwei(c))}
+	}
+
+	server := newRPCServer(t, map[string]rpcHandler{
+		"eth_blockNumber": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+			return hexutil.EncodeUint64(uint64(blockNumber.Load())), nil
+		},
+		"eth_feeHistory": func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError) {
+			feeHistoryCalls.Add(1)
+			assert.Equal(t, []interface{}{"0x3", hexutil.EncodeUint64(uint64(blockNumber.Load())), []interface{}{10.0, 50.0, 90.0}}, req.Params)
+			baseFee := hexutil.EncodeBig(gwei(10))
+			return map[string]interface{}{
+				"oldestBlock":   "0x1",
+				"baseFeePerGas": []interface{}{baseFee, baseFee, baseFee, baseFee},
+				"gasUsedRatio":  []interface{}{0.5, 0, 0.5},
+				"reward":        []interface{}{rewards(1, 2, 3), rewards(0, 0, 0), rewards(3, 4, 5)},
+			}, nil
+		},
+		"eth_maxPriorityFeePerGas": result(hexutil.EncodeBig(big.NewInt(4_500_000_000))),
+		"eth_gasPrice":             result(hexutil.EncodeBig(gwei(10))),
+	})
+	return New(server.URL)
+}
+
+func TestFeeOracle(t *testing.T) {
+	var blockNumber atomic.Int64
+	var feeHistoryCalls atomic.Int32
+	blockNumber.Store(3)
+	client := newFeeServer(t, &blockNumber, &feeHistoryCalls)
+	oracle := NewFeeOracle(client, &FeeOracleOptions{BlockCount: 3})
+
+	suggestions, err := oracle.Suggest(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(3), suggestions.BlockNumber)
+	assert.Equal(t, gwei(10), suggestions.BaseFee)
+	// Empty blocks are skipped, so the medians come from the first and last block.
+	assert.Equal(t, gwei(3), suggestions.Slow.MaxPriorityFeePerGas)
+	assert.Equal(t, gwei(23), suggestions.Slow.MaxFeePerGas)
+	// The node's suggested tip is a floor for normal fees.
+	assert.Equal(t, big.NewInt(4_500_000_000), suggestions.Normal.MaxPriorityFeePerGas)
+	assert.Equal(t, big.NewInt(24_500_000_000), suggestions.Normal.MaxFeePerGas)
+	assert.Equal(t, gwei(5), suggestions.Fast.MaxPriorityFeePerGas)
+	assert.Equal(t, gwei(25), suggestions.Fast.MaxFeePerGas)
+
+	// Suggestions are cached for the same block, and callers get their own copy.
+	suggestions.BaseFee.SetInt64(0)
+	suggestions.Fast.MaxFeePerGas.SetInt64(0)
+	cached, err := oracle.Suggest(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), feeHistoryCalls.Load())
+	assert.Equal(t, gwei(10), cached.BaseFee)
+	assert.Equal(t, gwei(25), cached.Fast.MaxFeePerGas)
+
+	blockNumber.Store(4)
+	suggestions, err = oracle.Suggest(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), suggestions.BlockNumber)
+	assert.Equal(t, int32(2), feeHistoryCalls.Load())
+}
+
+func TestFeeOracle_Populate(t *testing.T) {
+	var blockNumber atomic.Int64
+	var feeHistoryCalls atomic.Int32
+	blockNumber.Store(3)
+	oracle := NewFeeOracle(newFeeServer(t, &blockNumber, &feeHistoryCalls), &FeeOracleOptions{BlockCount: 3})
+
+	builder := transaction.NewBuilder(big.NewInt(transaction.ChainIDTempoTestnet)).
+		SetGas(100000).
+		SetFeeToken(transaction.AlphaUSDAddress)
+
+	quote, err := oracle.Populate(context.Background(), builder, FeeFast)
+	require.NoError(t, err)
+
+	tx := builder.Build()
+	assert.Equal(t, gwei(25), tx.MaxFeePerGas)
+	assert.Equal(t, gwei(5), tx.MaxPriorityFeePerGas)
+	assert.Equal(t, transaction.AlphaUSDAddress, quote.FeeToken)
+	assert.Equal(t, uint64(100000), quote.Gas)
+	// 100000 gas * 25 gwei = 2.5e15 attodollars = 2500 micro-units of the fee token.
+	assert.Equal(t, big.NewInt(2500), quote.MaxCost)
+}
+
+func TestFeeEstimate_MaxCost(t *testing.T) {
+	estimate := FeeEstimate{MaxFeePerGas: big.NewInt(10_000_000_001), MaxPriorityFeePerGas: big.NewInt(0)}
+	assert.Equal(t, big.NewInt(211), estimate.MaxCost(21000))
+	assert.Zero(t, estimate.MaxCost(0).Sign())
+}
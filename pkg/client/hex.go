@@ -0,0 +1,69 @@
+package client
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// HexUint64 is a uint64 encoded in JSON as a 0x-prefixed hex string, the format of
+// quantities such as block numbers and nonces.
+type HexUint64 uint64
+
+// MarshalText implements encoding.TextMarshaler.
+func (h HexUint64) MarshalText() ([]byte, error) {
+	return hexutil.Uint64(h).MarshalText()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (h *HexUint64) UnmarshalJSON(input []byte) error {
+	return (*hexutil.Uint64)(h).UnmarshalJSON(input)
+}
+
+// String returns the hex encoding of h.
+func (h HexUint64) String() string {
+	return hexutil.EncodeUint64(uint64(h))
+}
+
+// HexBig is an arbitrary-precision integer encoded in JSON as a 0x-prefixed hex
+// string, the format of quantities such as balances and chain IDs.
+type HexBig big.Int
+
+// Big returns h as a *big.Int. The result shares its value with h.
+func (h *HexBig) Big() *big.Int {
+	return (*big.Int)(h)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (h HexBig) MarshalText() ([]byte, error) {
+	return hexutil.Big(h).MarshalText()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (h *HexBig) UnmarshalJSON(input []byte) error {
+	return (*hexutil.Big)(h).UnmarshalJSON(input)
+}
+
+// String returns the hex encoding of h.
+func (h *HexBig) String() string {
+	return hexutil.EncodeBig(h.Big())
+}
+
+// HexBytes is a byte slice encoded in JSON as a 0x-prefixed hex string, the format of
+// call output, transaction input and log data.
+type HexBytes []byte
+
+// MarshalText implements encoding.TextMarshaler.
+func (h HexBytes) MarshalText() ([]byte, error) {
+	return hexutil.Bytes(h).MarshalText()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (h *HexBytes) UnmarshalJSON(input []byte) error {
+	return (*hexutil.Bytes)(h).UnmarshalJSON(input)
+}
+
+// String returns the hex encoding of h.
+func (h HexBytes) String() string {
+	return hexutil.Encode(h)
+}
@@ -0,0 +1,217 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Invocation is a JSON-RPC call passing through the interceptor chain: a single
+// request, or every request of a batch.
+type Invocation struct {
+	// Requests holds the request of a single call, or the requests of a batch.
+	// Interceptors may rewrite them before calling the next invoker.
+	Requests []*JSONRPCRequest
+
+	// Batch is true for calls made through SendBatch.
+	Batch bool
+
+	// Header holds extra HTTP headers sent with the call. Interceptors may add to it.
+	// Headers only apply to HTTP: over WebSocket and IPC, a call with headers fails.
+	// Use WithHeader for headers sent with the WebSocket handshake.
+	Header http.Header
+}
+
+// Method returns the method of a single call, or "batch" for a batch.
+func (c *Invocation) Method() string {
+	if c.Batch || len(c.Requests) != 1 {
+		return "batch"
+	}
+	return c.Requests[0].Method
+}
+
+// SetHeader sets an HTTP header sent with the call.
+func (c *Invocation) SetHeader(key, value string) {
+	if c.Header == nil {
+		c.Header = make(http.Header)
+	}
+	c.Header.Set(key, value)
+}
+
+// Invoker sends a call and returns one response per request. A single call returns
+// exactly one response. JSON-RPC errors are reported in the responses; the error is
+// only set when the call could not be completed.
+type Invoker func(ctx context.Context, call *Invocation) ([]*JSONRPCResponse, error)
+
+// Interceptor wraps every call made by a client, like an http.RoundTripper at the
+// JSON-RPC level. It sees the requests before they are sent and the responses or
+// error after, and must call next to continue the chain. Interceptors run once per
+// call, outside of retries and endpoint failover.
+type Interceptor interface {
+	Intercept(ctx context.Context, call *Invocation, next Invoker) ([]*JSONRPCResponse, error)
+}
+
+// InterceptorFunc adapts a function to the Interceptor interface.
+type InterceptorFunc func(ctx context.Context, call *Invocation, next Invoker) ([]*JSONRPCResponse, error)
+
+// Intercept calls f.
+func (f InterceptorFunc) Intercept(ctx context.Context, call *Invocation, next Invoker) ([]*JSONRPCResponse, error) {
+	return f(ctx, call, next)
+}
+
+// WithInterceptors adds interceptors to the client. The first interceptor is the
+// outermost: it sees calls first and responses last.
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(c *Client) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// invoke checks a call against the chain set with WithChainID and sends it through
+// the interceptor chain.
+func (c *Client) invoke(ctx context.Context, call *Invocation) ([]*JSONRPCResponse, error) {
+	if err := c.checkChain(ctx, call); err != nil {
+		return nil, err
+	}
+	next := Invoker(c.roundTrip)
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := c.interceptors[i], next
+		next = func(ctx context.Context, call *Invocation) ([]*JSONRPCResponse, error) {
+			return interceptor.Intercept(ctx, call, inner)
+		}
+	}
+	return next(ctx, call)
+}
+
+// roundTrip is the innermost invoker, which sends calls to the endpoints and matches
+// the responses to the requests.
+func (c *Client) roundTrip(ctx context.Context, call *Invocation) ([]*JSONRPCResponse, error) {
+	responses, err := c.send(ctx, call)
+	if err != nil {
+		return nil, err
+	}
+	return matchResponses(call.Requests, responses)
+}
+
+// send sends a call over the stream or to the endpoint pool.
+func (c *Client) send(ctx context.Context, call *Invocation) ([]*JSONRPCResponse, error) {
+	if c.stream != nil {
+		send := false
+		for _, request := range call.Requests {
+			send = send || isSendMethod(request.Method)
+		}
+		var responses []*JSONRPCResponse
+		err := c.withRetry(ctx, call.Method(), send, func() error {
+			var err error
+			responses, err = c.stream.call(ctx, call)
+			return err
+		})
+		return responses, err
+	}
+	if call.Batch {
+		return c.sendBatch(ctx, call)
+	}
+	if len(call.Requests) != 1 {
+		return nil, fmt.Errorf("single call with %d requests", len(call.Requests))
+	}
+	response, err := c.sendSingle(ctx, call)
+	if err != nil {
+		return nil, err
+	}
+	return []*JSONRPCResponse{response}, nil
+}
+
+// CallTiming describes a completed call. See TimingInterceptor.
+type CallTiming struct {
+	// Method is the method of a single call, or "batch".
+	Method string
+
+	// Size is the number of requests in the call.
+	Size int
+
+	// Duration is the time taken by the call, including retries.
+	Duration time.Duration
+
+	// Err is the error of the call, or else the first JSON-RPC error in its responses.
+	Err error
+}
+
+// TimingInterceptor reports the duration and outcome of every call to observe,
+// for example to record latency metrics.
+func TimingInterceptor(observe func(CallTiming)) Interceptor {
+	return InterceptorFunc(func(ctx context.Context, call *Invocation, next Invoker) ([]*JSONRPCResponse, error) {
+		start := time.Now()
+		responses, err := next(ctx, call)
+		observe(CallTiming{
+			Method:   call.Method(),
+			Size:     len(call.Requests),
+			Duration: time.Since(start),
+			Err:      callError(responses, err),
+		})
+		return responses, err
+	})
+}
+
+// LoggingInterceptor logs every call to logger: successful calls at debug level and
+// failed calls at warn level. Signed transactions in the params of send methods are
+// redacted to their length.
+func LoggingInterceptor(logger *slog.Logger) Interceptor {
+	return InterceptorFunc(func(ctx context.Context, call *Invocation, next Invoker) ([]*JSONRPCResponse, error) {
+		start := time.Now()
+		responses, err := next(ctx, call)
+
+		attrs := []slog.Attr{
+			slog.String("method", call.Method()),
+			slog.Duration("duration", time.Since(start)),
+		}
+		if call.Batch {
+			methods := make([]string, len(call.Requests))
+			for i, request := range call.Requests {
+				methods[i] = request.Method
+			}
+			attrs = append(attrs, slog.Int("size", len(call.Requests)), slog.Any("methods", methods))
+		} else if len(call.Requests) == 1 {
+			attrs = append(attrs, slog.Any("params", redactParams(call.Requests[0])))
+		}
+
+		level := slog.LevelDebug
+		if callErr := callError(responses, err); callErr != nil {
+			level = slog.LevelWarn
+			attrs = append(attrs, slog.String("error", callErr.Error()))
+		}
+		logger.LogAttrs(ctx, level, "rpc call", attrs...)
+		return responses, err
+	})
+}
+
+// callError returns err, or else the first JSON-RPC error in responses.
+func callError(responses []*JSONRPCResponse, err error) error {
+	if err != nil {
+		return err
+	}
+	for _, response := range responses {
+		if response != nil && response.Error != nil {
+			return response.Error
+		}
+	}
+	return nil
+}
+
+// redactParams returns the params of a request for logging, with signed transactions
+// replaced by their type prefix and length.
+func redactParams(request *JSONRPCRequest) []interface{} {
+	if !isSendMethod(request.Method) {
+		return request.Params
+	}
+	params := make([]interface{}, len(request.Params))
+	for i, param := range request.Params {
+		if raw, ok := param.(string); ok && len(raw) > 4 {
+			params[i] = fmt.Sprintf("%s…[redacted %d bytes]", raw[:4], (len(raw)-2)/2)
+			continue
+		}
+		params[i] = param
+	}
+	return params
+}
@@ -0,0 +1,171 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterceptors(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("order, rewriting and headers", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "abc", r.Header.Get("X-Request-Id"))
+			var req JSONRPCRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "eth_getBlockByNumber", req.Method)
+			assert.Equal(t, []interface{}{"finalized", false}, req.Params)
+			json.NewEncoder(w).Encode(NewJSONRPCResponse(req.ID, "ok"))
+		}))
+		t.Cleanup(server.Close)
+
+		var order []string
+		trace := func(name string) Interceptor {
+			return InterceptorFunc(func(ctx context.Context, call *Invocation, next Invoker) ([]*JSONRPCResponse, error) {
+				order = append(order, name+" before")
+				responses, err := next(ctx, call)
+				order = append(order, name+" after")
+				return responses, err
+			})
+		}
+		rewrite := InterceptorFunc(func(ctx context.Context, call *Invocation, next Invoker) ([]*JSONRPCResponse, error) {
+			call.SetHeader("X-Request-Id", "abc")
+			call.Requests[0].Params[0] = "finalized"
+			return next(ctx, call)
+		})
+
+		c := New(server.URL, WithInterceptors(trace("outer"), trace("inner")), WithInterceptors(rewrite))
+		response, err := c.SendRequest(ctx, "eth_getBlockByNumber", "latest", false)
+		require.NoError(t, err)
+		assert.Equal(t, "ok", response.Result)
+		assert.Equal(t, []string{"outer before", "inner before", "inner after", "outer after"}, order)
+	})
+
+	t.Run("short circuit", func(t *testing.T) {
+		cached := InterceptorFunc(func(ctx context.Context, call *Invocation, next Invoker) ([]*JSONRPCResponse, error) {
+			if call.Method() == "eth_chainId" {
+				return []*JSONRPCResponse{NewJSONRPCResponse(call.Requests[0].ID, "0xa5bf")}, nil
+			}
+			return next(ctx, call)
+		})
+
+		chainID, err := New("http://127.0.0.1:0", WithInterceptors(cached)).ChainID(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(42431), chainID.Int64())
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		server := newBatchServer(t)
+
+		var seen *Invocation
+		spy := InterceptorFunc(func(ctx context.Context, call *Invocation, next Invoker) ([]*JSONRPCResponse, error) {
+			seen = call
+			return next(ctx, call)
+		})
+
+		batch := NewBatchRequest().Add("eth_blockNumber").Add("eth_chainId")
+		responses, err := New(server.URL, WithInterceptors(spy)).SendBatch(ctx, batch)
+		require.NoError(t, err)
+		assert.Len(t, responses, 2)
+		require.NotNil(t, seen)
+		assert.True(t, seen.Batch)
+		assert.Equal(t, "batch", seen.Method())
+		assert.Len(t, seen.Requests, 2)
+	})
+
+	t.Run("wrong response count", func(t *testing.T) {
+		broken := InterceptorFunc(func(context.Context, *Invocation, Invoker) ([]*JSONRPCResponse, error) {
+			return nil, nil
+		})
+		_, err := New("http://127.0.0.1:0", WithInterceptors(broken)).SendRequest(ctx, "eth_blockNumber")
+		assert.Error(t, err)
+	})
+}
+
+// newBatchServer returns a server answering every request of a batch with its method name.
+func newBatchServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []JSONRPCRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqs))
+		responses := make([]*JSONRPCResponse, len(reqs))
+		for i, req := range reqs {
+			responses[i] = NewJSONRPCResponse(req.ID, req.Method)
+		}
+		json.NewEncoder(w).Encode(responses)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTimingInterceptor(t *testing.T) {
+	server := newRPCServer(t, map[string]rpcHandler{
+		"eth_blockNumber": result("0x1"),
+		"eth_call": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+			return nil, &JSONRPCError{Code: 3, Message: "execution reverted"}
+		},
+	})
+
+	var timings []CallTiming
+	c := New(server.URL, WithInterceptors(TimingInterceptor(func(timing CallTiming) {
+		timings = append(timings, timing)
+	})))
+
+	_, err := c.GetBlockNumber(context.Background())
+	require.NoError(t, err)
+	_, err = c.SendRequest(context.Background(), "eth_call")
+	require.NoError(t, err)
+
+	require.Len(t, timings, 2)
+	assert.Equal(t, "eth_blockNumber", timings[0].Method)
+	assert.Equal(t, 1, timings[0].Size)
+	assert.Positive(t, timings[0].Duration)
+	assert.NoError(t, timings[0].Err)
+	assert.True(t, errors.Is(timings[1].Err, ErrExecutionReverted))
+}
+
+func TestLoggingInterceptor(t *testing.T) {
+	rawTx := "0x76" + strings.Repeat("ab", 100)
+	server := newRPCServer(t, map[string]rpcHandler{
+		"eth_blockNumber": result("0x1"),
+		methodSendRawTransaction: func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+			return nil, &JSONRPCError{Code: -32000, Message: "nonce too low"}
+		},
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c := New(server.URL, WithInterceptors(LoggingInterceptor(logger)))
+
+	_, err := c.GetBlockNumber(context.Background())
+	require.NoError(t, err)
+	_, err = c.SendRawTransaction(context.Background(), rawTx)
+	require.Error(t, err)
+
+	assert.NotContains(t, buf.String(), rawTx)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var read, send map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &read))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &send))
+
+	assert.Equal(t, "DEBUG", read["level"])
+	assert.Equal(t, "eth_blockNumber", read["method"])
+	assert.Contains(t, read, "duration")
+
+	assert.Equal(t, "WARN", send["level"])
+	assert.Equal(t, []interface{}{"0x76…[redacted 101 bytes]"}, send["params"])
+	assert.Equal(t, "RPC error -32000: nonce too low", send["error"])
+}
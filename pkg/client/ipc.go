@@ -0,0 +1,79 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ipcPath returns the socket path of an IPC endpoint, and false if rawURL is not one.
+// IPC endpoints are given as unix:// URLs or as filesystem paths, either absolute or
+// ending in ".ipc".
+func ipcPath(rawURL string) (string, bool) {
+	if strings.HasPrefix(rawURL, "unix:") {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return "", false
+		}
+		if u.Path != "" {
+			return u.Path, true
+		}
+		return u.Opaque, u.Opaque != ""
+	}
+	if strings.Contains(rawURL, "://") {
+		return "", false
+	}
+	return rawURL, strings.HasPrefix(rawURL, "/") || strings.HasSuffix(rawURL, ".ipc")
+}
+
+// newIPCTransport creates a transport that dials the Unix domain socket at path.
+// Messages larger than maxMessageSize bytes close the connection.
+func newIPCTransport(path string, maxMessageSize int64) *streamTransport {
+	return newStreamTransport(func(ctx context.Context) (streamCodec, error) {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "unix", path)
+		if err != nil {
+			return nil, err
+		}
+		reader := &limitedReader{r: bufio.NewReader(conn)}
+		return &ipcCodec{
+			conn:           conn,
+			reader:         reader,
+			decoder:        json.NewDecoder(reader),
+			maxMessageSize: maxMessageSize,
+		}, nil
+	})
+}
+
+// ipcCodec sends JSON-RPC messages over a stream socket, one per line. Incoming
+// messages are read as a stream of JSON values, so they need not end in newlines.
+type ipcCodec struct {
+	conn    net.Conn
+	reader  *limitedReader
+	decoder *json.Decoder
+
+	// maxMessageSize bounds the bytes read from the socket for each message. Bytes the
+	// decoder read ahead count against the message they were read for.
+	maxMessageSize int64
+}
+
+func (c *ipcCodec) writeMessage(data []byte) error {
+	_, err := c.conn.Write(append(data, '\n'))
+	return err
+}
+
+func (c *ipcCodec) readMessage() ([]byte, error) {
+	c.reader.remaining = c.maxMessageSize
+	var message json.RawMessage
+	if err := c.decoder.Decode(&message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+func (c *ipcCodec) close() error {
+	return c.conn.Close()
+}
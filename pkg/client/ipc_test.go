@@ -0,0 +1,194 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ipcServerConn adapts a server-side Unix socket connection to fakeStreamConn.
+type ipcServerConn struct {
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func (c *ipcServerConn) send(data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := c.conn.Write(append(data, '\n'))
+	return err
+}
+
+func (c *ipcServerConn) close() {
+	c.conn.Close()
+}
+
+// newIPCNode starts node behind a Unix domain socket and returns the socket path.
+func newIPCNode(t *testing.T, node *fakeStreamNode) string {
+	t.Helper()
+	// Socket paths are limited to about 100 bytes, too short for t.TempDir on some systems.
+	dir, err := os.MkdirTemp("", "tempo")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "node.ipc")
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			reader := bufio.NewReader(conn)
+			go node.serve(&ipcServerConn{conn: conn}, func() ([]byte, error) {
+				return reader.ReadBytes('\n')
+			})
+		}
+	}()
+	return path
+}
+
+func newIPCClient(t *testing.T, node *fakeStreamNode, opts ...Option) *Client {
+	t.Helper()
+	c := New(newIPCNode(t, node), opts...)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestIPCPath(t *testing.T) {
+	tests := []struct {
+		url  string
+		path string
+		ok   bool
+	}{
+		{"/var/run/tempo/tempo.ipc", "/var/run/tempo/tempo.ipc", true},
+		{"/tmp/node.sock", "/tmp/node.sock", true},
+		{"data/tempo.ipc", "data/tempo.ipc", true},
+		{"unix:///var/run/tempo.ipc", "/var/run/tempo.ipc", true},
+		{"unix:tempo.ipc", "tempo.ipc", true},
+		{"https://rpc.testnet.tempo.xyz", "", false},
+		{"wss://rpc.testnet.tempo.xyz", "", false},
+		{"localhost:8545", "localhost:8545", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			path, ok := ipcPath(tt.url)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.path, path)
+			}
+		})
+	}
+}
+
+func TestIPCTransport(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("calls and batches", func(t *testing.T) {
+		node := newFakeStreamNode(t, map[string]rpcHandler{
+			"eth_blockNumber": result("0x10"),
+			"eth_chainId":     result("0xa5bf"),
+		})
+		c := newIPCClient(t, node)
+
+		number, err := c.GetBlockNumber(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(16), number)
+
+		responses, err := c.SendBatch(ctx, NewBatchRequest().Add("eth_blockNumber").Add("eth_chainId"))
+		require.NoError(t, err)
+		require.Len(t, responses, 2)
+		assert.Equal(t, "0x10", responses[0].Result)
+		assert.Equal(t, "0xa5bf", responses[1].Result)
+		assert.Equal(t, 1, node.dialCount())
+	})
+
+	t.Run("unix URL", func(t *testing.T) {
+		node := newFakeStreamNode(t, map[string]rpcHandler{"eth_blockNumber": result("0x2")})
+		c := New("unix://" + newIPCNode(t, node))
+		defer c.Close()
+
+		number, err := c.GetBlockNumber(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), number)
+	})
+
+	t.Run("subscriptions share the connection", func(t *testing.T) {
+		node := newFakeStreamNode(t, map[string]rpcHandler{"eth_blockNumber": result("0x10")})
+		c := newIPCClient(t, node)
+
+		sub, err := c.SubscribeNewHeads(ctx)
+		require.NoError(t, err)
+		defer sub.Unsubscribe()
+
+		node.notify("newHeads", map[string]interface{}{"number": "0x11"})
+		select {
+		case head := <-sub.C():
+			assert.Equal(t, uint64(17), head.Number)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for head")
+		}
+
+		_, err = c.GetBlockNumber(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, node.dialCount())
+	})
+
+	t.Run("resubscribes after reconnect", func(t *testing.T) {
+		node := newFakeStreamNode(t, nil)
+		c := newIPCClient(t, node)
+
+		sub, err := c.SubscribeNewHeads(ctx)
+		require.NoError(t, err)
+		defer sub.Unsubscribe()
+
+		node.dropConnections()
+		assert.Eventually(t, func() bool { return node.dialCount() == 2 }, 5*time.Second, 5*time.Millisecond)
+		node.waitForSubscriptions(t, 1)
+
+		node.notify("newHeads", map[string]interface{}{"number": "0x20"})
+		select {
+		case head := <-sub.C():
+			assert.Equal(t, uint64(32), head.Number)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for head after reconnect")
+		}
+	})
+
+	t.Run("message larger than the limit", func(t *testing.T) {
+		node := newFakeStreamNode(t, map[string]rpcHandler{
+			"eth_getCode":     result("0x" + strings.Repeat("00", 4096)),
+			"eth_blockNumber": result("0x10"),
+		})
+		c := newIPCClient(t, node, WithMaxResponseSize(1024))
+
+		_, err := c.SendRequest(ctx, "eth_getCode")
+		assert.Error(t, err)
+
+		// The connection is re-established for the next call.
+		number, err := c.GetBlockNumber(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(16), number)
+		assert.Equal(t, 2, node.dialCount())
+	})
+
+	t.Run("missing socket", func(t *testing.T) {
+		c := New(filepath.Join(t.TempDir(), "missing.ipc"))
+		defer c.Close()
+
+		_, err := c.GetBlockNumber(ctx)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// NoncePrecompileAddress is the address of Tempo's nonce precompile, which tracks
+// the sequence of every non-zero nonce key of an account.
+var NoncePrecompileAddress = common.HexToAddress("0x4E4F4E4345000000000000000000000000000000")
+
+// getNonceSelector is the function selector of getNonce(address,uint256).
+var getNonceSelector = []byte{0x89, 0x53, 0x58, 0x03}
+
+// GetNonce gets the next nonce of an account for a nonce key at the given block.
+// Nonce key 0 (or nil) is the protocol nonce, read with eth_getTransactionCount;
+// other keys are read from the nonce precompile.
+func (c *Client) GetNonce(ctx context.Context, address common.Address, nonceKey *big.Int, block BlockSelector) (uint64, error) {
+	method, params := nonceRequest(address, nonceKey, block)
+	if isProtocolNonceKey(nonceKey) {
+		var count hexutil.Uint64
+		if err := c.call(ctx, &count, method, params...); err != nil {
+			return 0, err
+		}
+		return uint64(count), nil
+	}
+
+	var result hexutil.Bytes
+	if err := c.call(ctx, &result, method, params...); err != nil {
+		return 0, fmt.Errorf("failed to get nonce for key %s: %w", nonceKey, err)
+	}
+	return decodeNonce(result)
+}
+
+// GetNonces gets the next nonce of an account for each of the given nonce keys at the
+// given block, using a single batch request. The returned nonces are in the same order
+// as nonceKeys. If any lookup fails, the first error is returned.
+func (c *Client) GetNonces(ctx context.Context, address common.Address, nonceKeys []*big.Int, block BlockSelector) ([]uint64, error) {
+	batch := NewBatchRequest()
+	for _, key := range nonceKeys {
+		method, params := nonceRequest(address, key, block)
+		batch.Add(method, params...)
+	}
+
+	responses, err := c.SendBatch(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	nonces := make([]uint64, len(nonceKeys))
+	for i, key := range nonceKeys {
+		if err := responses[i].CheckError(); err != nil {
+			return nil, fmt.Errorf("failed to get nonce for key %s: %w", key, err)
+		}
+		if isProtocolNonceKey(key) {
+			var count hexutil.Uint64
+			if err := responses[i].DecodeResult(&count); err != nil {
+				return nil, err
+			}
+			nonces[i] = uint64(count)
+			continue
+		}
+
+		var result hexutil.Bytes
+		if err := responses[i].DecodeResult(&result); err != nil {
+			return nil, err
+		}
+		if nonces[i], err = decodeNonce(result); err != nil {
+			return nil, fmt.Errorf("failed to get nonce for key %s: %w", key, err)
+		}
+	}
+	return nonces, nil
+}
+
+func isProtocolNonceKey(nonceKey *big.Int) bool {
+	return nonceKey == nil || nonceKey.Sign() == 0
+}
+
+// nonceRequest returns the JSON-RPC method and params that read the nonce of a key.
+func nonceRequest(address common.Address, nonceKey *big.Int, block BlockSelector) (string, []interface{}) {
+	if isProtocolNonceKey(nonceKey) {
+		return "eth_getTransactionCount", []interface{}{address, block}
+	}
+
+	data := make([]byte, 0, 68)
+	data = append(data, getNonceSelector...)
+	data = append(data, common.LeftPadBytes(address.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(nonceKey.Bytes(), 32)...)
+
+	msg := map[string]interface{}{
+		"to":   NoncePrecompileAddress,
+		"data": hexutil.Bytes(data),
+	}
+	return "eth_call", []interface{}{msg, block}
+}
+
+// decodeNonce decodes the uint64 returned by the nonce precompile.
+func decodeNonce(output []byte) (uint64, error) {
+	if len(output) != 32 {
+		return 0, fmt.Errorf("unexpected getNonce result length %d", len(output))
+	}
+	nonce := new(big.Int).SetBytes(output)
+	if !nonce.IsUint64() {
+		return 0, fmt.Errorf("getNonce result %s overflows uint64", nonce)
+	}
+	return nonce.Uint64(), nil
+}
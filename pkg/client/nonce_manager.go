@@ -0,0 +1,229 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tempoxyz/tempo-go/pkg/transaction"
+)
+
+// NonceReader reads the next nonces of an account for a set of nonce keys.
+// *Client implements this interface.
+type NonceReader interface {
+	GetNonces(ctx context.Context, address common.Address, nonceKeys []*big.Int, block BlockSelector) ([]uint64, error)
+}
+
+// Nonce is a (NonceKey, Nonce) pair handed out by a NonceManager.
+type Nonce struct {
+	Key   *big.Int
+	Nonce uint64
+}
+
+// Apply sets the nonce key and nonce of a transaction builder.
+func (n Nonce) Apply(b *transaction.Builder) *transaction.Builder {
+	return b.SetNonceKey(new(big.Int).Set(n.Key)).SetNonce(n.Nonce)
+}
+
+// nonceLane tracks the nonces of a single nonce key.
+type nonceLane struct {
+	key *big.Int
+
+	// next is the next nonce that has never been handed out.
+	next uint64
+
+	// released holds nonces below next that were handed out but never sent, in
+	// ascending order. They are reused first, since later nonces of the lane cannot
+	// be included until the gap is filled.
+	released []uint64
+}
+
+// NonceManager hands out (NonceKey, Nonce) pairs for an account across a pool of
+// nonce keys, so that independent transactions do not wait on each other.
+// Nonces are synced from the chain on first use and whenever a "nonce too low"
+// error is reported through HandleError.
+// NonceManager is safe for concurrent use.
+type NonceManager struct {
+	reader  NonceReader
+	address common.Address
+
+	// syncMu serializes chain reads so concurrent callers share one initial sync.
+	syncMu sync.Mutex
+
+	mu     sync.Mutex
+	lanes  []*nonceLane
+	cursor int
+	synced bool
+}
+
+// NewNonceManager creates a nonce manager for address that spreads transactions over
+// the given nonce keys. If no keys are given, only the protocol nonce key 0 is used.
+func NewNonceManager(reader NonceReader, address common.Address, nonceKeys ...*big.Int) *NonceManager {
+	if len(nonceKeys) == 0 {
+		nonceKeys = []*big.Int{big.NewInt(transaction.DefaultNonceKey)}
+	}
+
+	m := &NonceManager{reader: reader, address: address}
+	for _, key := range nonceKeys {
+		m.lanes = append(m.lanes, &nonceLane{key: new(big.Int).Set(key)})
+	}
+	return m
+}
+
+// Sync reads the pending nonce of every nonce key from the chain, discarding all
+// local state. Call it when no transactions are in flight.
+func (m *NonceManager) Sync(ctx context.Context) error {
+	m.syncMu.Lock()
+	defer m.syncMu.Unlock()
+	return m.sync(ctx)
+}
+
+func (m *NonceManager) sync(ctx context.Context) error {
+	nonces, err := m.reader.GetNonces(ctx, m.address, m.keys(), Pending)
+	if err != nil {
+		return fmt.Errorf("failed to sync nonces: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, lane := range m.lanes {
+		lane.next = nonces[i]
+		lane.released = nil
+	}
+	m.synced = true
+	return nil
+}
+
+// Next hands out the next nonce. Released nonces are reused first; otherwise nonce
+// keys are used in turn. Each nonce must eventually be sent or passed to Release.
+func (m *NonceManager) Next(ctx context.Context) (Nonce, error) {
+	if err := m.ensureSynced(ctx); err != nil {
+		return Nonce{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, lane := range m.lanes {
+		if len(lane.released) > 0 {
+			nonce := lane.released[0]
+			lane.released = lane.released[1:]
+			return Nonce{Key: new(big.Int).Set(lane.key), Nonce: nonce}, nil
+		}
+	}
+
+	lane := m.lanes[m.cursor]
+	m.cursor = (m.cursor + 1) % len(m.lanes)
+	nonce := lane.next
+	lane.next++
+	return Nonce{Key: new(big.Int).Set(lane.key), Nonce: nonce}, nil
+}
+
+// Release returns a nonce whose transaction was never sent, so it is handed out again.
+func (m *NonceManager) Release(n Nonce) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lane := m.lane(n.Key)
+	if lane == nil || n.Nonce >= lane.next {
+		return
+	}
+	if n.Nonce == lane.next-1 {
+		lane.next--
+		// Released nonces directly below are now at the top of the lane as well.
+		for len(lane.released) > 0 && lane.released[len(lane.released)-1] == lane.next-1 {
+			lane.released = lane.released[:len(lane.released)-1]
+			lane.next--
+		}
+		return
+	}
+
+	i := sort.Search(len(lane.released), func(i int) bool { return lane.released[i] >= n.Nonce })
+	if i < len(lane.released) && lane.released[i] == n.Nonce {
+		return
+	}
+	lane.released = append(lane.released, 0)
+	copy(lane.released[i+1:], lane.released[i:])
+	lane.released[i] = n.Nonce
+}
+
+// Resync reads the pending nonce of a nonce key from the chain. Nonces the chain has
+// already consumed are discarded; nonces handed out beyond it are kept, since their
+// transactions may still be in flight.
+func (m *NonceManager) Resync(ctx context.Context, nonceKey *big.Int) error {
+	nonces, err := m.reader.GetNonces(ctx, m.address, []*big.Int{nonceKey}, Pending)
+	if err != nil {
+		return fmt.Errorf("failed to sync nonce key %s: %w", nonceKey, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lane := m.lane(nonceKey)
+	if lane == nil {
+		return fmt.Errorf("nonce key %s is not managed", nonceKey)
+	}
+	chainNonce := nonces[0]
+	if chainNonce > lane.next {
+		lane.next = chainNonce
+	}
+	i := sort.Search(len(lane.released), func(i int) bool { return lane.released[i] >= chainNonce })
+	lane.released = lane.released[i:]
+	return nil
+}
+
+// HandleError updates the manager after sending a transaction with nonce n failed.
+// A "nonce too low" error resyncs the nonce key from the chain; any other error
+// releases the nonce for reuse. Only call it when the transaction was rejected, not
+// when the outcome is unknown (for example after a timeout).
+func (m *NonceManager) HandleError(ctx context.Context, n Nonce, err error) error {
+	if errors.Is(err, ErrNonceTooLow) {
+		return m.Resync(ctx, n.Key)
+	}
+	m.Release(n)
+	return nil
+}
+
+func (m *NonceManager) ensureSynced(ctx context.Context) error {
+	m.mu.Lock()
+	synced := m.synced
+	m.mu.Unlock()
+	if synced {
+		return nil
+	}
+
+	m.syncMu.Lock()
+	defer m.syncMu.Unlock()
+
+	m.mu.Lock()
+	synced = m.synced
+	m.mu.Unlock()
+	if synced {
+		return nil
+	}
+	return m.sync(ctx)
+}
+
+func (m *NonceManager) keys() []*big.Int {
+	keys := make([]*big.Int, len(m.lanes))
+	for i, lane := range m.lanes {
+		keys[i] = lane.key
+	}
+	return keys
+}
+
+func (m *NonceManager) lane(key *big.Int) *nonceLane {
+	if key == nil {
+		key = big.NewInt(transaction.DefaultNonceKey)
+	}
+	for _, lane := range m.lanes {
+		if lane.key.Cmp(key) == 0 {
+			return lane
+		}
+	}
+	return nil
+}
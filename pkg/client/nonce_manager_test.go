@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNonceReader serves chain nonces from a map keyed by nonce key.
+type fakeNonceReader struct {
+	mu     sync.Mutex
+	nonces map[string]uint64
+	calls  int
+}
+
+func (f *fakeNonceReader) GetNonces(_ context.Context, _ common.Address, keys []*big.Int, _ BlockSelector) ([]uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	nonces := make([]uint64, len(keys))
+	for i, key := range keys {
+		nonces[i] = f.nonces[key.String()]
+	}
+	return nonces, nil
+}
+
+func (f *fakeNonceReader) set(key int64, nonce uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nonces[big.NewInt(key).String()] = nonce
+}
+
+func nextNonce(t *testing.T, m *NonceManager) Nonce {
+	t.Helper()
+	n, err := m.Next(context.Background())
+	require.NoError(t, err)
+	return n
+}
+
+func TestNonceManager_Next(t *testing.T) {
+	reader := &fakeNonceReader{nonces: map[string]uint64{"1": 4, "2": 0}}
+	m := NewNonceManager(reader, testAccount, big.NewInt(1), big.NewInt(2))
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		n := nextNonce(t, m)
+		got = append(got, fmt.Sprintf("%s/%d", n.Key, n.Nonce))
+	}
+	assert.Equal(t, []string{"1/4", "2/0", "1/5", "2/1"}, got)
+	assert.Equal(t, 1, reader.calls, "nonces are synced once on first use")
+}
+
+func TestNonceManager_DefaultKey(t *testing.T) {
+	reader := &fakeNonceReader{nonces: map[string]uint64{"0": 7}}
+	m := NewNonceManager(reader, testAccount)
+
+	n := nextNonce(t, m)
+	assert.Equal(t, 0, n.Key.Sign())
+	assert.Equal(t, uint64(7), n.Nonce)
+}
+
+func TestNonceManager_Release(t *testing.T) {
+	reader := &fakeNonceReader{nonces: map[string]uint64{"1": 0}}
+	m := NewNonceManager(reader, testAccount, big.NewInt(1))
+
+	n0, n1, n2 := nextNonce(t, m), nextNonce(t, m), nextNonce(t, m)
+
+	// A released gap is filled before new nonces are handed out.
+	m.Release(n1)
+	assert.Equal(t, uint64(1), nextNonce(t, m).Nonce)
+	assert.Equal(t, uint64(3), nextNonce(t, m).Nonce)
+
+	// Releasing the top nonces rewinds the lane, collapsing released gaps below.
+	m.Release(n0)
+	m.Release(Nonce{Key: big.NewInt(1), Nonce: 3})
+	m.Release(n2)
+	assert.Equal(t, uint64(0), nextNonce(t, m).Nonce)
+	assert.Equal(t, uint64(2), nextNonce(t, m).Nonce)
+
+	// Unknown keys and nonces never handed out are ignored.
+	m.Release(Nonce{Key: big.NewInt(9), Nonce: 0})
+	m.Release(Nonce{Key: big.NewInt(1), Nonce: 100})
+	assert.Equal(t, uint64(3), nextNonce(t, m).Nonce)
+}
+
+func TestNonceManager_HandleError(t *testing.T) {
+	reader := &fakeNonceReader{nonces: map[string]uint64{"1": 0}}
+	m := NewNonceManager(reader, testAccount, big.NewInt(1))
+
+	n := nextNonce(t, m)
+	require.NoError(t, m.HandleError(context.Background(), n, errors.New("connection refused")))
+	assert.Equal(t, uint64(0), nextNonce(t, m).Nonce, "rejected nonces are reused")
+
+	// Another sender consumed nonces on this key.
+	reader.set(1, 5)
+	tooLow := fmt.Errorf("eth_sendRawTransaction: %w", &JSONRPCError{Code: -32000, Message: "nonce too low"})
+	require.NoError(t, m.HandleError(context.Background(), Nonce{Key: big.NewInt(1), Nonce: 0}, tooLow))
+	assert.Equal(t, uint64(5), nextNonce(t, m).Nonce)
+}
+
+func TestNonceManager_Concurrent(t *testing.T) {
+	reader := &fakeNonceReader{nonces: map[string]uint64{"1": 10, "2": 20, "3": 30}}
+	m := NewNonceManager(reader, testAccount, big.NewInt(1), big.NewInt(2), big.NewInt(3))
+
+	const workers = 50
+	results := make(chan Nonce, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n, err := m.Next(context.Background())
+			assert.NoError(t, err)
+			results <- n
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	seen := make(map[string]bool)
+	for n := range results {
+		id := fmt.Sprintf("%s/%d", n.Key, n.Nonce)
+		assert.False(t, seen[id], "duplicate nonce %s", id)
+		seen[id] = true
+	}
+	assert.Len(t, seen, workers)
+	assert.Equal(t, 1, reader.calls)
+}
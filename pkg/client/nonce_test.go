@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// getNonceCallData is the getNonce(testAccount, 5) call data.
+const getNonceCallData = "0x89535803" +
+	"000000000000000000000000f39fd6e51aad88f6f4ce6ab8827279cfffb92266" +
+	"0000000000000000000000000000000000000000000000000000000000000005"
+
+// nonceResult ABI-encodes a nonce as the precompile returns it.
+func nonceResult(nonce uint64) string {
+	return hexutil.Encode(new(big.Int).SetUint64(nonce).FillBytes(make([]byte, 32)))
+}
+
+func TestGetNonce(t *testing.T) {
+	server := newRPCServer(t, map[string]rpcHandler{
+		"eth_call": func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError) {
+			msg := req.Params[0].(map[string]interface{})
+			assert.Equal(t, "0x4e4f4e4345000000000000000000000000000000", msg["to"])
+			assert.Equal(t, getNonceCallData, msg["data"])
+			assert.Equal(t, "pending", req.Params[1])
+			return nonceResult(9), nil
+		},
+		"eth_getTransactionCount": func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError) {
+			assert.Equal(t, []interface{}{"0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266", "latest"}, req.Params)
+			return "0x3", nil
+		},
+	})
+	client := New(server.URL)
+
+	nonce, err := client.GetNonce(context.Background(), testAccount, big.NewInt(5), Pending)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(9), nonce)
+
+	// Nonce key 0 is the protocol nonce.
+	nonce, err = client.GetNonce(context.Background(), testAccount, big.NewInt(0), Latest)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), nonce)
+}
+
+func TestGetNonces(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []JSONRPCRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqs))
+		require.Len(t, reqs, 3)
+
+		// Answer in reverse order to check responses are matched by ID.
+		responses := make([]*JSONRPCResponse, 0, len(reqs))
+		for i := len(reqs) - 1; i >= 0; i-- {
+			req := reqs[i]
+			switch req.Method {
+			case "eth_getTransactionCount":
+				responses = append(responses, NewJSONRPCResponse(req.ID, "0x3"))
+			case "eth_call":
+				data := req.Params[0].(map[string]interface{})["data"].(string)
+				key := new(big.Int).SetBytes(hexutil.MustDecode(data)[36:])
+				responses = append(responses, NewJSONRPCResponse(req.ID, nonceResult(key.Uint64()*10)))
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(responses)
+	}))
+	defer server.Close()
+
+	nonces, err := New(server.URL).GetNonces(context.Background(), testAccount,
+		[]*big.Int{big.NewInt(1), nil, big.NewInt(7)}, Pending)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{10, 3, 70}, nonces)
+}
+
+func TestGetNonces_ItemError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []JSONRPCRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqs))
+
+		responses := []*JSONRPCResponse{
+			NewJSONRPCResponse(reqs[0].ID, nonceResult(1)),
+			NewJSONRPCErrorResponse(reqs[1].ID, InternalError, "precompile failure", nil),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(responses)
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL).GetNonces(context.Background(), testAccount,
+		[]*big.Int{big.NewInt(1), big.NewInt(2)}, Latest)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "key 2")
+	assert.Contains(t, err.Error(), "precompile failure")
+}
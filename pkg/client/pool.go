@@ -0,0 +1,415 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// endpointCooldown is how long an endpoint that failed a request is skipped when no
+// health check is configured to bring it back.
+const endpointCooldown = 30 * time.Second
+
+// Endpoint is an additional RPC endpoint of a client. See WithEndpoints.
+type Endpoint struct {
+	URL string
+
+	// Username and Password configure basic authentication for this endpoint only.
+	// They take precedence over credentials given in the URL.
+	Username string
+	Password string
+}
+
+// Routing selects how requests are spread over the endpoints of a client.
+type Routing int
+
+const (
+	// RoundRobin sends each request to the next available endpoint in turn.
+	RoundRobin Routing = iota
+
+	// LowestLatency sends each request to the available endpoint with the lowest
+	// observed latency.
+	LowestLatency
+)
+
+// HealthCheckOptions configures periodic endpoint health checks. See WithHealthCheck.
+type HealthCheckOptions struct {
+	// Interval between health checks. Defaults to 10s.
+	Interval time.Duration
+
+	// Timeout of each eth_blockNumber probe. Defaults to 5s.
+	Timeout time.Duration
+
+	// MaxBlockLag is how many blocks an endpoint may trail the highest block seen
+	// across all endpoints before it is excluded. Defaults to 5.
+	MaxBlockLag uint64
+}
+
+// EndpointStatus is a snapshot of the state of an endpoint.
+type EndpointStatus struct {
+	URL string
+
+	// Available is false while the endpoint is failing or lagging.
+	Available bool
+
+	// Lagging is true if the last health check found the endpoint too far behind.
+	Lagging bool
+
+	// Latency is a moving average of request latency, or zero before the first request.
+	Latency time.Duration
+
+	// BlockNumber is the block number seen by the last health check.
+	BlockNumber uint64
+}
+
+// WithEndpoints adds RPC endpoints to the client. The URL given to New, with the
+// credentials from WithAuth, is the first endpoint of the pool.
+//
+// Requests are routed to available endpoints (see WithRouting). If a request fails
+// with a transport error, HTTP 5xx or a rate limit, the endpoint is skipped for a
+// while and the same request body is sent to the next endpoint. This is safe for raw
+// transaction sends as well, since the signed bytes are identical.
+func WithEndpoints(endpoints ...Endpoint) Option {
+	return func(c *Client) {
+		c.extraEndpoints = append(c.extraEndpoints, endpoints...)
+	}
+}
+
+// WithRouting configures how requests are spread over the endpoints. Defaults to RoundRobin.
+func WithRouting(routing Routing) Option {
+	return func(c *Client) {
+		c.routing = routing
+	}
+}
+
+// WithBroadcastSends sends raw transactions to every available endpoint at once, so
+// they reach the network even if some nodes are slow to gossip. The send succeeds if
+// any endpoint accepts the transaction.
+func WithBroadcastSends() Option {
+	return func(c *Client) {
+		c.broadcastSends = true
+	}
+}
+
+// WithHealthCheck probes every endpoint with eth_blockNumber in the background.
+// Endpoints that fail the probe, or trail the highest block by more than MaxBlockLag,
+// are excluded until a later probe succeeds. If opts is nil, defaults are used.
+// Call Close to stop the health checks.
+func WithHealthCheck(opts *HealthCheckOptions) Option {
+	var o HealthCheckOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Interval <= 0 {
+		o.Interval = 10 * time.Second
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 5 * time.Second
+	}
+	if o.MaxBlockLag == 0 {
+		o.MaxBlockLag = 5
+	}
+	return func(c *Client) {
+		c.healthCheck = &o
+	}
+}
+
+// endpoint is the runtime state of an RPC endpoint.
+type endpoint struct {
+	url      string
+	username string
+	password string
+
+	mu          sync.Mutex
+	checkFailed bool
+	lagging     bool
+	downUntil   time.Time
+	latency     time.Duration
+	blockNumber uint64
+}
+
+func (e *endpoint) available(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return !e.checkFailed && !e.lagging && !now.Before(e.downUntil)
+}
+
+// observe records the latency of a successful request.
+func (e *endpoint) observe(latency time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.latency == 0 {
+		e.latency = latency
+	} else {
+		e.latency = (4*e.latency + latency) / 5
+	}
+}
+
+// fail skips the endpoint until the cooldown passes or a health check succeeds.
+func (e *endpoint) fail(now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.downUntil = now.Add(endpointCooldown)
+}
+
+func (e *endpoint) status(now time.Time) EndpointStatus {
+	available := e.available(now)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return EndpointStatus{
+		URL:         redactURL(e.url),
+		Available:   available,
+		Lagging:     e.lagging,
+		Latency:     e.latency,
+		BlockNumber: e.blockNumber,
+	}
+}
+
+// initEndpoints builds the endpoint pool once all options are applied.
+func (c *Client) initEndpoints() {
+	c.endpoints = []*endpoint{{url: c.rpcURL, username: c.username, password: c.password}}
+	for _, e := range c.extraEndpoints {
+		rawURL, username, password := splitCredentials(e.URL)
+		if e.Username != "" || e.Password != "" {
+			username, password = e.Username, e.Password
+		}
+		c.endpoints = append(c.endpoints, &endpoint{url: rawURL, username: username, password: password})
+	}
+	if c.healthCheck != nil {
+		c.stop = make(chan struct{})
+		c.stopped = make(chan struct{})
+		go c.runHealthChecks()
+	}
+}
+
+// Endpoints returns the status of every endpoint, starting with the URL given to New.
+func (c *Client) Endpoints() []EndpointStatus {
+	now := time.Now()
+	statuses := make([]EndpointStatus, len(c.endpoints))
+	for i, e := range c.endpoints {
+		statuses[i] = e.status(now)
+	}
+	return statuses
+}
+
+// Close stops background health checks and closes WebSocket and IPC connections,
+// ending all subscriptions. HTTP clients remain usable after Close.
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		if c.stop != nil {
+			close(c.stop)
+			<-c.stopped
+		}
+		if c.stream != nil {
+			c.stream.close()
+		}
+	})
+	return nil
+}
+
+// pick returns the endpoint for the next request, skipping endpoints in tried.
+// If no endpoint is available, untried endpoints are used anyway, so a pool that
+// is entirely down is still attempted. Returns nil once every endpoint was tried.
+func (c *Client) pick(tried map[*endpoint]bool) *endpoint {
+	now := time.Now()
+	var candidates, fallback []*endpoint
+	for _, e := range c.endpoints {
+		if tried[e] {
+			continue
+		}
+		if e.available(now) {
+			candidates = append(candidates, e)
+		} else {
+			fallback = append(fallback, e)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = fallback
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	if c.routing == LowestLatency {
+		best := candidates[0]
+		bestLatency := best.status(now).Latency
+		for _, e := range candidates[1:] {
+			if latency := e.status(now).Latency; latency < bestLatency {
+				best, bestLatency = e, latency
+			}
+		}
+		return best
+	}
+	n := c.cursor.Add(1) - 1
+	return candidates[n%uint64(len(candidates))]
+}
+
+// failover calls try with endpoints from the pool until it succeeds, fails with an
+// error that is not retryable, or every endpoint was tried. Endpoints that fail with
+// a retryable error are skipped by later requests for a while.
+func (c *Client) failover(ctx context.Context, try func(e *endpoint) error) error {
+	tried := make(map[*endpoint]bool)
+	for {
+		e := c.pick(tried)
+		if e == nil {
+			return errors.New("no RPC endpoint available")
+		}
+		tried[e] = true
+
+		err := try(e)
+		if err == nil || !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+		e.fail(time.Now())
+		if len(tried) == len(c.endpoints) {
+			return err
+		}
+	}
+}
+
+// broadcast sends a request body to every available endpoint concurrently and returns
+// the first successful response. If no endpoint accepts the request, the first
+// JSON-RPC error response is returned, or else the first error.
+func (c *Client) broadcast(ctx context.Context, body []byte, header http.Header) (*JSONRPCResponse, error) {
+	now := time.Now()
+	var targets []*endpoint
+	for _, e := range c.endpoints {
+		if e.available(now) {
+			targets = append(targets, e)
+		}
+	}
+	if len(targets) == 0 {
+		targets = c.endpoints
+	}
+
+	type result struct {
+		response *JSONRPCResponse
+		err      error
+	}
+	results := make(chan result, len(targets))
+	for _, e := range targets {
+		go func(e *endpoint) {
+			var response JSONRPCResponse
+			if err := c.post(ctx, e, body, header, "HTTP request", "response", &response); err != nil {
+				if retryable(err) {
+					e.fail(time.Now())
+				}
+				results <- result{err: err}
+				return
+			}
+			results <- result{response: &response}
+		}(e)
+	}
+
+	var rejected *JSONRPCResponse
+	var firstErr error
+	for range targets {
+		r := <-results
+		switch {
+		case r.response != nil && r.response.Error == nil:
+			return r.response, nil
+		case r.response != nil && rejected == nil:
+			rejected = r.response
+		case r.err != nil && firstErr == nil:
+			firstErr = r.err
+		}
+	}
+	if rejected != nil {
+		return rejected, nil
+	}
+	return nil, firstErr
+}
+
+func (c *Client) runHealthChecks() {
+	defer close(c.stopped)
+
+	check := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), c.healthCheck.Timeout)
+		defer cancel()
+		c.CheckHealth(ctx)
+	}
+
+	check()
+	ticker := time.NewTicker(c.healthCheck.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
+
+// CheckHealth probes every endpoint with eth_blockNumber and updates which endpoints
+// are available. It runs periodically when WithHealthCheck is set, and can be called
+// directly otherwise. Returns an error only if every endpoint failed.
+func (c *Client) CheckHealth(ctx context.Context) error {
+	maxBlockLag := uint64(5)
+	if c.healthCheck != nil {
+		maxBlockLag = c.healthCheck.MaxBlockLag
+	}
+
+	request, err := json.Marshal(c.newRequest("eth_blockNumber"))
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	blockNumbers := make([]uint64, len(c.endpoints))
+	errs := make([]error, len(c.endpoints))
+	var wg sync.WaitGroup
+	for i, e := range c.endpoints {
+		wg.Add(1)
+		go func(i int, e *endpoint) {
+			defer wg.Done()
+			blockNumbers[i], errs[i] = c.probe(ctx, e, request)
+		}(i, e)
+	}
+	wg.Wait()
+
+	var highest uint64
+	for i := range c.endpoints {
+		if errs[i] == nil && blockNumbers[i] > highest {
+			highest = blockNumbers[i]
+		}
+	}
+
+	var failed []error
+	for i, e := range c.endpoints {
+		e.mu.Lock()
+		e.checkFailed = errs[i] != nil
+		if errs[i] == nil {
+			e.blockNumber = blockNumbers[i]
+			e.lagging = highest-blockNumbers[i] > maxBlockLag
+			e.downUntil = time.Time{}
+		} else {
+			failed = append(failed, fmt.Errorf("%s: %w", redactURL(e.url), errs[i]))
+		}
+		e.mu.Unlock()
+	}
+	if len(failed) == len(c.endpoints) {
+		return errors.Join(failed...)
+	}
+	return nil
+}
+
+// probe reads the block number of a single endpoint.
+func (c *Client) probe(ctx context.Context, e *endpoint, request []byte) (uint64, error) {
+	var response JSONRPCResponse
+	if err := c.post(ctx, e, request, nil, "HTTP request", "response", &response); err != nil {
+		return 0, err
+	}
+	if err := response.CheckError(); err != nil {
+		return 0, err
+	}
+	blockNumHex, ok := response.Result.(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected result type: %T", response.Result)
+	}
+	return parseHexUint64(blockNumHex)
+}
@@ -0,0 +1,246 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// poolNode is a fake RPC node for endpoint pool tests.
+type poolNode struct {
+	*httptest.Server
+
+	blockNumber atomic.Uint64
+	status      atomic.Int32 // HTTP status to fail with, or 0
+	delay       time.Duration
+	sendErr     *JSONRPCError
+
+	mu      sync.Mutex
+	methods []string
+}
+
+func newPoolNode(t *testing.T, blockNumber uint64, username, password string) *poolNode {
+	t.Helper()
+	node := &poolNode{}
+	node.blockNumber.Store(blockNumber)
+	node.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req JSONRPCRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		user, pass, _ := r.BasicAuth()
+		assert.Equal(t, username, user)
+		assert.Equal(t, password, pass)
+
+		node.mu.Lock()
+		node.methods = append(node.methods, req.Method)
+		node.mu.Unlock()
+
+		time.Sleep(node.delay)
+		if status := node.status.Load(); status != 0 {
+			w.WriteHeader(int(status))
+			return
+		}
+
+		resp := NewJSONRPCResponse(req.ID, fmt.Sprintf("0x%x", node.blockNumber.Load()))
+		if req.Method == methodSendRawTransaction {
+			resp = NewJSONRPCResponse(req.ID, "0x01")
+			if node.sendErr != nil {
+				resp = NewJSONRPCErrorResponse(req.ID, node.sendErr.Code, node.sendErr.Message, nil)
+			}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(node.Close)
+	return node
+}
+
+func (n *poolNode) calls(method string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	count := 0
+	for _, m := range n.methods {
+		if m == method {
+			count++
+		}
+	}
+	return count
+}
+
+func TestEndpointPool(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("round robin with per-endpoint auth", func(t *testing.T) {
+		a := newPoolNode(t, 10, "alice", "secret-a")
+		b := newPoolNode(t, 10, "bob", "secret-b")
+		c := New(a.URL, WithAuth("alice", "secret-a"),
+			WithEndpoints(Endpoint{URL: b.URL, Username: "bob", Password: "secret-b"}))
+
+		for i := 0; i < 4; i++ {
+			_, err := c.GetBlockNumber(ctx)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, a.calls("eth_blockNumber"))
+		assert.Equal(t, 2, b.calls("eth_blockNumber"))
+	})
+
+	t.Run("failover", func(t *testing.T) {
+		a := newPoolNode(t, 10, "", "")
+		b := newPoolNode(t, 11, "", "")
+		a.status.Store(http.StatusBadGateway)
+		c := New(a.URL, WithEndpoints(Endpoint{URL: b.URL}))
+
+		for i := 0; i < 3; i++ {
+			number, err := c.GetBlockNumber(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(11), number)
+		}
+		// The failing endpoint is skipped after its first failure.
+		assert.Equal(t, 1, a.calls("eth_blockNumber"))
+		assert.Equal(t, 3, b.calls("eth_blockNumber"))
+
+		statuses := c.Endpoints()
+		assert.False(t, statuses[0].Available)
+		assert.True(t, statuses[1].Available)
+	})
+
+	t.Run("all endpoints failing", func(t *testing.T) {
+		a := newPoolNode(t, 10, "", "")
+		b := newPoolNode(t, 10, "", "")
+		a.status.Store(http.StatusServiceUnavailable)
+		b.status.Store(http.StatusServiceUnavailable)
+		c := New(a.URL, WithEndpoints(Endpoint{URL: b.URL}))
+
+		_, err := c.GetBlockNumber(ctx)
+		var httpErr *HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+
+		// Unavailable endpoints are still tried when nothing else is left.
+		a.status.Store(0)
+		_, err = c.GetBlockNumber(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("permanent errors do not fail over", func(t *testing.T) {
+		a := newPoolNode(t, 10, "", "")
+		b := newPoolNode(t, 10, "", "")
+		a.status.Store(http.StatusUnauthorized)
+		b.status.Store(http.StatusUnauthorized)
+		c := New(a.URL, WithEndpoints(Endpoint{URL: b.URL}))
+
+		_, err := c.GetBlockNumber(ctx)
+		require.Error(t, err)
+		assert.Equal(t, 1, a.calls("eth_blockNumber")+b.calls("eth_blockNumber"))
+	})
+
+	t.Run("health check excludes lagging and failing endpoints", func(t *testing.T) {
+		a := newPoolNode(t, 100, "", "")
+		b := newPoolNode(t, 90, "", "")
+		d := newPoolNode(t, 100, "", "")
+		d.status.Store(http.StatusInternalServerError)
+		c := New(a.URL, WithEndpoints(Endpoint{URL: b.URL}, Endpoint{URL: d.URL}))
+
+		require.NoError(t, c.CheckHealth(ctx))
+		statuses := c.Endpoints()
+		assert.True(t, statuses[0].Available)
+		assert.Equal(t, uint64(100), statuses[0].BlockNumber)
+		assert.False(t, statuses[1].Available)
+		assert.True(t, statuses[1].Lagging)
+		assert.False(t, statuses[2].Available)
+
+		for i := 0; i < 3; i++ {
+			_, err := c.GetBlockNumber(ctx)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 4, a.calls("eth_blockNumber"))
+		assert.Equal(t, 1, b.calls("eth_blockNumber"))
+
+		// Endpoints come back once they catch up.
+		b.blockNumber.Store(98)
+		d.status.Store(0)
+		require.NoError(t, c.CheckHealth(ctx))
+		for _, status := range c.Endpoints() {
+			assert.True(t, status.Available)
+		}
+	})
+
+	t.Run("health check fails when every endpoint fails", func(t *testing.T) {
+		a := newPoolNode(t, 100, "", "")
+		a.status.Store(http.StatusBadGateway)
+		assert.Error(t, New(a.URL).CheckHealth(ctx))
+	})
+
+	t.Run("background health check", func(t *testing.T) {
+		a := newPoolNode(t, 100, "", "")
+		b := newPoolNode(t, 50, "", "")
+		c := New(a.URL, WithEndpoints(Endpoint{URL: b.URL}),
+			WithHealthCheck(&HealthCheckOptions{Interval: 10 * time.Millisecond, MaxBlockLag: 10}))
+		defer c.Close()
+
+		assert.Eventually(t, func() bool {
+			return c.Endpoints()[1].Lagging
+		}, time.Second, 5*time.Millisecond)
+
+		b.blockNumber.Store(100)
+		assert.Eventually(t, func() bool {
+			return c.Endpoints()[1].Available
+		}, time.Second, 5*time.Millisecond)
+
+		require.NoError(t, c.Close())
+		require.NoError(t, c.Close())
+	})
+
+	t.Run("lowest latency", func(t *testing.T) {
+		slow := newPoolNode(t, 10, "", "")
+		slow.delay = 30 * time.Millisecond
+		fast := newPoolNode(t, 10, "", "")
+		c := New(slow.URL, WithEndpoints(Endpoint{URL: fast.URL}), WithRouting(LowestLatency))
+
+		require.NoError(t, c.CheckHealth(ctx))
+		for i := 0; i < 3; i++ {
+			_, err := c.GetBlockNumber(ctx)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 1, slow.calls("eth_blockNumber"))
+		assert.Equal(t, 4, fast.calls("eth_blockNumber"))
+		assert.Greater(t, c.Endpoints()[0].Latency, c.Endpoints()[1].Latency)
+	})
+
+	t.Run("broadcast sends", func(t *testing.T) {
+		a := newPoolNode(t, 10, "", "")
+		b := newPoolNode(t, 10, "", "")
+		a.sendErr = &JSONRPCError{Code: -32000, Message: "nonce too low"}
+		c := New(a.URL, WithEndpoints(Endpoint{URL: b.URL}), WithBroadcastSends())
+
+		hash, err := c.SendRawTransaction(ctx, "0x76")
+		require.NoError(t, err)
+		assert.Equal(t, "0x01", hash)
+		assert.Equal(t, 1, a.calls(methodSendRawTransaction))
+		assert.Equal(t, 1, b.calls(methodSendRawTransaction))
+
+		// Reads are not broadcast.
+		_, err = c.GetBlockNumber(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, a.calls("eth_blockNumber")+b.calls("eth_blockNumber"))
+	})
+
+	t.Run("broadcast rejected everywhere", func(t *testing.T) {
+		a := newPoolNode(t, 10, "", "")
+		b := newPoolNode(t, 10, "", "")
+		a.sendErr = &JSONRPCError{Code: -32000, Message: "nonce too low"}
+		b.status.Store(http.StatusBadGateway)
+		c := New(a.URL, WithEndpoints(Endpoint{URL: b.URL}), WithBroadcastSends())
+
+		_, err := c.SendRawTransaction(ctx, "0x76")
+		assert.ErrorIs(t, err, ErrNonceTooLow)
+	})
+}
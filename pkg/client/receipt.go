@@ -0,0 +1,381 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/tempoxyz/tempo-go/pkg/transaction"
+)
+
+const (
+	// ReceiptStatusFailed is the status of a transaction that reverted.
+	ReceiptStatusFailed = 0
+
+	// ReceiptStatusSuccessful is the status of a transaction that executed successfully.
+	ReceiptStatusSuccessful = 1
+
+	defaultPollInterval    = time.Second
+	defaultMaxPollInterval = 10 * time.Second
+	defaultBackoffFactor   = 1.5
+)
+
+// feeTokenScale converts gas prices to fee token units. Tempo prices gas in
+// attodollars (10^-18 USD) while TIP-20 fee tokens have 6 decimals.
+var feeTokenScale = big.NewInt(1_000_000_000_000)
+
+// Receipt is the receipt of an included transaction, including Tempo-specific fee fields.
+type Receipt struct {
+	TxHash            common.Hash
+	TxIndex           uint64
+	BlockHash         common.Hash
+	BlockNumber       uint64
+	From              common.Address
+	To                *common.Address // nil for contract creation
+	ContractAddress   *common.Address // set for contract creation
+	Type              uint8
+	Status            uint64
+	GasUsed           uint64
+	CumulativeGasUsed uint64
+	EffectiveGasPrice *big.Int
+	Logs              []*Log
+	LogsBloom         []byte
+
+	// FeeToken is the token the transaction fee was paid in.
+	FeeToken *common.Address
+
+	// FeePayer is the account that paid the fee (the sender unless sponsored).
+	FeePayer *common.Address
+}
+
+// Log is an event emitted during transaction execution.
+type Log struct {
+	Address     common.Address
+	Topics      []common.Hash
+	Data        []byte
+	BlockNumber uint64
+	BlockHash   common.Hash
+	TxHash      common.Hash
+	TxIndex     uint64
+	Index       uint64
+	Removed     bool
+}
+
+type receiptJSON struct {
+	TxHash            common.Hash     `json:"transactionHash"`
+	TxIndex           hexutil.Uint64  `json:"transactionIndex"`
+	BlockHash         common.Hash     `json:"blockHash"`
+	BlockNumber       hexutil.Uint64  `json:"blockNumber"`
+	From              common.Address  `json:"from"`
+	To                *common.Address `json:"to"`
+	ContractAddress   *common.Address `json:"contractAddress"`
+	Type              hexutil.Uint64  `json:"type"`
+	Status            hexutil.Uint64  `json:"status"`
+	GasUsed           hexutil.Uint64  `json:"gasUsed"`
+	CumulativeGasUsed hexutil.Uint64  `json:"cumulativeGasUsed"`
+	EffectiveGasPrice *hexutil.Big    `json:"effectiveGasPrice"`
+	Logs              []*Log          `json:"logs"`
+	LogsBloom         hexutil.Bytes   `json:"logsBloom"`
+	FeeToken          *common.Address `json:"feeToken"`
+	FeePayer          *common.Address `json:"feePayer"`
+}
+
+type logJSON struct {
+	Address     common.Address `json:"address"`
+	Topics      []common.Hash  `json:"topics"`
+	Data        hexutil.Bytes  `json:"data"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	TxHash      common.Hash    `json:"transactionHash"`
+	TxIndex     hexutil.Uint64 `json:"transactionIndex"`
+	Index       hexutil.Uint64 `json:"logIndex"`
+	Removed     bool           `json:"removed"`
+}
+
+// UnmarshalJSON decodes a receipt from its JSON-RPC representation.
+func (r *Receipt) UnmarshalJSON(data []byte) error {
+	var dec receiptJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+
+	*r = Receipt{
+		TxHash:            dec.TxHash,
+		TxIndex:           uint64(dec.TxIndex),
+		BlockHash:         dec.BlockHash,
+		BlockNumber:       uint64(dec.BlockNumber),
+		From:              dec.From,
+		To:                dec.To,
+		ContractAddress:   dec.ContractAddress,
+		Type:              uint8(dec.Type),
+		Status:            uint64(dec.Status),
+		GasUsed:           uint64(dec.GasUsed),
+		CumulativeGasUsed: uint64(dec.CumulativeGasUsed),
+		EffectiveGasPrice: (*big.Int)(dec.EffectiveGasPrice),
+		Logs:              dec.Logs,
+		LogsBloom:         dec.LogsBloom,
+		FeeToken:          dec.FeeToken,
+		FeePayer:          dec.FeePayer,
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes a log from its JSON-RPC representation.
+func (l *Log) UnmarshalJSON(data []byte) error {
+	var dec logJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+
+	*l = Log{
+		Address:     dec.Address,
+		Topics:      dec.Topics,
+		Data:        dec.Data,
+		BlockNumber: uint64(dec.BlockNumber),
+		BlockHash:   dec.BlockHash,
+		TxHash:      dec.TxHash,
+		TxIndex:     uint64(dec.TxIndex),
+		Index:       uint64(dec.Index),
+		Removed:     dec.Removed,
+	}
+	return nil
+}
+
+// Succeeded returns true if the transaction executed successfully.
+func (r *Receipt) Succeeded() bool {
+	return r.Status == ReceiptStatusSuccessful
+}
+
+// FeePaid returns the fee charged for the transaction in fee token units,
+// computed as GasUsed * EffectiveGasPrice and rounded up to whole token units.
+func (r *Receipt) FeePaid() *big.Int {
+	if r.EffectiveGasPrice == nil {
+		return big.NewInt(0)
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(r.GasUsed), r.EffectiveGasPrice)
+	fee.Add(fee, new(big.Int).Sub(feeTokenScale, big.NewInt(1)))
+	return fee.Div(fee, feeTokenScale)
+}
+
+// GetTransactionReceipt gets the receipt of an included transaction.
+// Returns ErrNotFound if the transaction is unknown or still pending.
+func (c *Client) GetTransactionReceipt(ctx context.Context, txHash common.Hash) (*Receipt, error) {
+	var receipt *Receipt
+	if err := c.call(ctx, &receipt, "eth_getTransactionReceipt", txHash); err != nil {
+		return nil, err
+	}
+	if receipt == nil {
+		return nil, fmt.Errorf("%w: receipt for %s", ErrNotFound, txHash.Hex())
+	}
+	return receipt, nil
+}
+
+// SendRawTransactionSyncReceipt broadcasts a raw transaction synchronously and returns
+// the receipt reported by the node once the transaction is included in a block.
+// Nodes that only report the hash are asked for the receipt.
+func (c *Client) SendRawTransactionSyncReceipt(ctx context.Context, serializedTx string) (*Receipt, error) {
+	txHash, receipt, err := c.sendRawTransactionSync(ctx, serializedTx)
+	if err != nil {
+		return nil, err
+	}
+	if receipt == nil {
+		return c.GetTransactionReceipt(ctx, common.HexToHash(txHash))
+	}
+	return receipt, nil
+}
+
+// SendTransactionSyncReceipt is like SendTransactionSync but returns the receipt of
+// the included transaction, as SendRawTransactionSyncReceipt.
+func (c *Client) SendTransactionSyncReceipt(ctx context.Context, tx *transaction.Tx) (*Receipt, error) {
+	serializedTx, localHash, err := c.prepareTransaction(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := c.SendRawTransactionSyncReceipt(ctx, serializedTx)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.TxHash != localHash {
+		return nil, fmt.Errorf("%w: node returned %s, expected %s", ErrTxHashMismatch, receipt.TxHash.Hex(), localHash.Hex())
+	}
+	return receipt, nil
+}
+
+// sendRawTransactionSync sends eth_sendRawTransactionSync and returns the hash of the
+// included transaction, along with its receipt if the node returned one.
+func (c *Client) sendRawTransactionSync(ctx context.Context, serializedTx string) (string, *Receipt, error) {
+	response, err := c.sendRequest(ctx, c.newRequest(methodSendRawTransactionSync, serializedTx))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to send %s request to %s: %w", methodSendRawTransactionSync, redactURL(c.rpcURL), err)
+	}
+
+	if err := response.CheckError(); err != nil {
+		return "", nil, fmt.Errorf("%s: %w", methodSendRawTransactionSync, err)
+	}
+
+	if txHash, ok := response.Result.(string); ok {
+		return txHash, nil, nil
+	}
+
+	var receipt *Receipt
+	if err := response.DecodeResult(&receipt); err != nil {
+		return "", nil, err
+	}
+	if receipt == nil {
+		return "", nil, fmt.Errorf("%s: empty result", methodSendRawTransactionSync)
+	}
+	return receipt.TxHash.Hex(), receipt, nil
+}
+
+// WaitOptions configures WaitForReceipt.
+// Zero values are replaced with defaults.
+type WaitOptions struct {
+	// PollInterval is the delay before the first retry. Defaults to 1s.
+	PollInterval time.Duration
+
+	// MaxPollInterval caps the delay between polls. Defaults to 10s.
+	MaxPollInterval time.Duration
+
+	// BackoffFactor multiplies the delay after every unsuccessful poll. Defaults to 1.5.
+	// Use 1 to poll at a fixed interval.
+	BackoffFactor float64
+
+	// Confirmations is the number of blocks that must be built on top of the block
+	// including the transaction before the receipt is returned.
+	Confirmations uint64
+
+	// Timeout bounds the total wait. If zero, only the context bounds the wait.
+	Timeout time.Duration
+}
+
+// WaitTimeoutError is returned by WaitForReceipt when the wait times out.
+// It unwraps to context.DeadlineExceeded.
+type WaitTimeoutError struct {
+	TxHash  common.Hash
+	Elapsed time.Duration
+
+	// Receipt is set if the transaction was included but did not reach the
+	// requested number of confirmations in time.
+	Receipt *Receipt
+
+	// LastErr is the transient error of the last poll, if it failed.
+	LastErr error
+}
+
+// Error implements the error interface for WaitTimeoutError.
+func (e *WaitTimeoutError) Error() string {
+	var msg string
+	if e.Receipt != nil {
+		msg = fmt.Sprintf("timed out after %s waiting for confirmations of transaction %s (included in block %d)",
+			e.Elapsed, e.TxHash.Hex(), e.Receipt.BlockNumber)
+	} else {
+		msg = fmt.Sprintf("timed out after %s waiting for receipt of transaction %s", e.Elapsed, e.TxHash.Hex())
+	}
+	if e.LastErr != nil {
+		msg += fmt.Sprintf(": last error: %v", e.LastErr)
+	}
+	return msg
+}
+
+// Unwrap returns context.DeadlineExceeded and LastErr, if set.
+func (e *WaitTimeoutError) Unwrap() []error {
+	if e.LastErr != nil {
+		return []error{context.DeadlineExceeded, e.LastErr}
+	}
+	return []error{context.DeadlineExceeded}
+}
+
+// WaitForReceipt polls for the receipt of a transaction until it is included and has
+// the requested number of confirmations. Pass nil opts to use the defaults.
+// Transient failures, such as transport errors and rate limits, are retried; other
+// errors are returned immediately.
+//
+// Example:
+//
+//	receipt, err := client.WaitForReceipt(ctx, hash, &client.WaitOptions{
+//		Confirmations: 2,
+//		Timeout:       time.Minute,
+//	})
+//	var timeout *client.WaitTimeoutError
+//	if errors.As(err, &timeout) {
+//		// still pending
+//	}
+func (c *Client) WaitForReceipt(ctx context.Context, txHash common.Hash, opts *WaitOptions) (*Receipt, error) {
+	o := WaitOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = defaultPollInterval
+	}
+	if o.MaxPollInterval <= 0 {
+		o.MaxPollInterval = defaultMaxPollInterval
+	}
+	if o.MaxPollInterval < o.PollInterval {
+		o.MaxPollInterval = o.PollInterval
+	}
+	if o.BackoffFactor < 1 {
+		o.BackoffFactor = defaultBackoffFactor
+	}
+
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	delay := o.PollInterval
+	var included *Receipt
+	var lastErr error
+
+	for {
+		// Transient errors, such as transport failures and rate limits, are retried
+		// until the context expires. Other errors end the wait.
+		receipt, err := c.GetTransactionReceipt(ctx, txHash)
+		if err == nil {
+			included = receipt
+			if o.Confirmations == 0 {
+				return receipt, nil
+			}
+			var head uint64
+			head, err = c.GetBlockNumber(ctx)
+			if err == nil && head >= receipt.BlockNumber+o.Confirmations {
+				return receipt, nil
+			}
+		} else if errors.Is(err, ErrNotFound) {
+			// Not included yet, or dropped from the canonical chain by a reorg.
+			included = nil
+			err = nil
+		}
+		// Errors caused by the context ending are reported by the select below.
+		if ctx.Err() == nil {
+			if err != nil && !retryable(err) {
+				return nil, err
+			}
+			lastErr = err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, &WaitTimeoutError{TxHash: txHash, Elapsed: time.Since(start), Receipt: included, LastErr: lastErr}
+			}
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		delay = time.Duration(float64(delay) * o.BackoffFactor)
+		if delay > o.MaxPollInterval {
+			delay = o.MaxPollInterval
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempoxyz/tempo-go/pkg/transaction"
+)
+
+const testTxHash = "0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060"
+
+func testReceiptJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"transactionHash":   testTxHash,
+		"transactionIndex":  "0x1",
+		"blockHash":         "0x2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0",
+		"blockNumber":       "0x10",
+		"from":              "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266",
+		"to":                "0x20c0000000000000000000000000000000000001",
+		"contractAddress":   nil,
+		"type":              "0x76",
+		"status":            "0x1",
+		"gasUsed":           "0x5208",
+		"cumulativeGasUsed": "0xa410",
+		"effectiveGasPrice": "0x2540be400",
+		"logsBloom":         "0x00",
+		"feeToken":          "0x20c0000000000000000000000000000000000001",
+		"feePayer":          "0x3c44cdddb6a900fa2b585dd299e03d12fa4293bc",
+		"logs": []interface{}{
+			map[string]interface{}{
+				"address":          "0x20c0000000000000000000000000000000000001",
+				"topics":           []interface{}{"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"},
+				"data":             "0x0000000000000000000000000000000000000000000000000000000000000064",
+				"blockNumber":      "0x10",
+				"transactionHash":  testTxHash,
+				"transactionIndex": "0x1",
+				"logIndex":         "0x3",
+				"removed":          false,
+			},
+		},
+	}
+}
+
+func TestGetTransactionReceipt(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_getTransactionReceipt": func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError) {
+				assert.Equal(t, testTxHash, req.Params[0])
+				return testReceiptJSON(), nil
+			},
+		})
+
+		receipt, err := New(server.URL).GetTransactionReceipt(context.Background(), common.HexToHash(testTxHash))
+		require.NoError(t, err)
+
+		assert.Equal(t, common.HexToHash(testTxHash), receipt.TxHash)
+		assert.Equal(t, uint64(16), receipt.BlockNumber)
+		assert.Equal(t, uint8(0x76), receipt.Type)
+		assert.True(t, receipt.Succeeded())
+		assert.Equal(t, uint64(21000), receipt.GasUsed)
+		assert.Equal(t, big.NewInt(10000000000), receipt.EffectiveGasPrice)
+		assert.Nil(t, receipt.ContractAddress)
+		assert.Equal(t, common.HexToAddress("0x20c0000000000000000000000000000000000001"), *receipt.FeeToken)
+		assert.Equal(t, common.HexToAddress("0x3c44cdddb6a900fa2b585dd299e03d12fa4293bc"), *receipt.FeePayer)
+
+		require.Len(t, receipt.Logs, 1)
+		assert.Equal(t, uint64(3), receipt.Logs[0].Index)
+		assert.Len(t, receipt.Logs[0].Topics, 1)
+		assert.Equal(t, byte(0x64), receipt.Logs[0].Data[31])
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_getTransactionReceipt": result(nil),
+		})
+
+		_, err := New(server.URL).GetTransactionReceipt(context.Background(), common.HexToHash(testTxHash))
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+}
+
+func TestReceipt_FeePaid(t *testing.T) {
+	tests := []struct {
+		name     string
+		gasUsed  uint64
+		gasPrice *big.Int
+		want     int64
+	}{
+		{name: "exact", gasUsed: 21000, gasPrice: big.NewInt(20_000_000_000_000), want: 420000},
+		{name: "rounded up", gasUsed: 21000, gasPrice: big.NewInt(10_000_000_001), want: 211},
+		{name: "missing price", gasUsed: 21000, gasPrice: nil, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt := &Receipt{GasUsed: tt.gasUsed, EffectiveGasPrice: tt.gasPrice}
+			assert.Equal(t, big.NewInt(tt.want), receipt.FeePaid())
+		})
+	}
+}
+
+func TestWaitForReceipt(t *testing.T) {
+	fastPoll := &WaitOptions{PollInterval: time.Millisecond, BackoffFactor: 1}
+
+	t.Run("polls until included", func(t *testing.T) {
+		var polls atomic.Int32
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_getTransactionReceipt": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+				if polls.Add(1) < 3 {
+					return nil, nil
+				}
+				return testReceiptJSON(), nil
+			},
+		})
+
+		receipt, err := New(server.URL).WaitForReceipt(context.Background(), common.HexToHash(testTxHash), fastPoll)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(16), receipt.BlockNumber)
+		assert.Equal(t, int32(3), polls.Load())
+	})
+
+	t.Run("waits for confirmations", func(t *testing.T) {
+		var head atomic.Int64
+		head.Store(16)
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_getTransactionReceipt": result(testReceiptJSON()),
+			"eth_blockNumber": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+				return "0x" + big.NewInt(head.Add(1)).Text(16), nil
+			},
+		})
+
+		opts := *fastPoll
+		opts.Confirmations = 3
+		receipt, err := New(server.URL).WaitForReceipt(context.Background(), common.HexToHash(testTxHash), &opts)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(16), receipt.BlockNumber)
+		assert.Equal(t, int64(19), head.Load())
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_getTransactionReceipt": result(nil),
+		})
+
+		opts := *fastPoll
+		opts.Timeout = 20 * time.Millisecond
+		_, err := New(server.URL).WaitForReceipt(context.Background(), common.HexToHash(testTxHash), &opts)
+
+		var timeoutErr *WaitTimeoutError
+		require.True(t, errors.As(err, &timeoutErr))
+		assert.Equal(t, common.HexToHash(testTxHash), timeoutErr.TxHash)
+		assert.Nil(t, timeoutErr.Receipt)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+
+	t.Run("timeout keeps the last transient error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		opts := *fastPoll
+		opts.Timeout = 20 * time.Millisecond
+		_, err := New(server.URL).WaitForReceipt(context.Background(), common.HexToHash(testTxHash), &opts)
+
+		var timeoutErr *WaitTimeoutError
+		require.True(t, errors.As(err, &timeoutErr))
+		var httpErr *HTTPError
+		require.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Contains(t, err.Error(), "last error")
+	})
+
+	t.Run("permanent error ends the wait", func(t *testing.T) {
+		var polls atomic.Int32
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_getTransactionReceipt": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+				polls.Add(1)
+				return nil, &JSONRPCError{Code: MethodNotFound, Message: "method not found"}
+			},
+		})
+
+		_, err := New(server.URL).WaitForReceipt(context.Background(), common.HexToHash(testTxHash), fastPoll)
+		var rpcErr *JSONRPCError
+		require.True(t, errors.As(err, &rpcErr))
+		assert.Equal(t, MethodNotFound, rpcErr.Code)
+		assert.Equal(t, int32(1), polls.Load())
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_getTransactionReceipt": result(nil),
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := New(server.URL).WaitForReceipt(ctx, common.HexToHash(testTxHash), fastPoll)
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+}
+
+func TestSendRawTransactionSyncReceipt(t *testing.T) {
+	t.Run("receipt result", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_sendRawTransactionSync": result(testReceiptJSON()),
+		})
+		client := New(server.URL)
+
+		receipt, err := client.SendRawTransactionSyncReceipt(context.Background(), "0x76...")
+		require.NoError(t, err)
+		assert.Equal(t, common.HexToHash(testTxHash), receipt.TxHash)
+
+		hash, err := client.SendRawTransactionSync(context.Background(), "0x76...")
+		require.NoError(t, err)
+		assert.Equal(t, testTxHash, hash)
+	})
+
+	t.Run("hash result", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_sendRawTransactionSync": result(testTxHash),
+			"eth_getTransactionReceipt":  result(testReceiptJSON()),
+		})
+
+		receipt, err := New(server.URL).SendRawTransactionSyncReceipt(context.Background(), "0x76...")
+		require.NoError(t, err)
+		assert.Equal(t, uint64(16), receipt.BlockNumber)
+	})
+
+	t.Run("signed transaction", func(t *testing.T) {
+		tx := newSignedTx(t, transaction.ChainIDTempoTestnet)
+		hash, err := tx.Hash()
+		require.NoError(t, err)
+		receiptJSON := testReceiptJSON()
+		receiptJSON["transactionHash"] = hash.Hex()
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_chainId":                result("0xa5bd"),
+			"eth_sendRawTransactionSync": result(receiptJSON),
+		})
+		client := New(server.URL)
+
+		receipt, err := client.SendTransactionSyncReceipt(context.Background(), tx)
+		require.NoError(t, err)
+		assert.Equal(t, hash, receipt.TxHash)
+		assert.Equal(t, uint64(16), receipt.BlockNumber)
+
+		// A receipt for another transaction is refused.
+		server = newRPCServer(t, map[string]rpcHandler{
+			"eth_chainId":                result("0xa5bd"),
+			"eth_sendRawTransactionSync": result(testReceiptJSON()),
+		})
+		_, err = New(server.URL).SendTransactionSyncReceipt(context.Background(), tx)
+		assert.ErrorIs(t, err, ErrTxHashMismatch)
+	})
+}
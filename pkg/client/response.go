@@ -0,0 +1,167 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	// defaultMaxResponseSize is the largest response accepted unless
+	// WithMaxResponseSize is set.
+	defaultMaxResponseSize = 128 * 1024 * 1024
+
+	// maxErrorBodySize is the largest part of a non-200 response body kept in an
+	// HTTPError.
+	maxErrorBodySize = 1024
+
+	// maxDrainSize bounds how much of an unread response body is discarded so that
+	// the connection can be reused.
+	maxDrainSize = 64 * 1024
+
+	// gzipMinSize is the smallest request body compressed when WithGzip is set.
+	gzipMinSize = 1024
+)
+
+// WithMaxResponseSize limits the size of HTTP responses, and of WebSocket and IPC
+// messages, accepted from the node, after decompression. Larger responses fail with
+// ErrResponseTooLarge. Defaults to 128 MiB.
+func WithMaxResponseSize(n int64) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxResponseSize = n
+		}
+	}
+}
+
+// WithGzip compresses request bodies of 1 KiB or more with gzip and asks the node
+// for gzip-compressed responses. Only use it with nodes that accept compressed
+// requests. Responses are decompressed transparently in any case when the HTTP
+// client's transport has compression enabled, the default.
+func WithGzip() Option {
+	return func(c *Client) {
+		c.gzip = true
+	}
+}
+
+// responseLimit returns the largest response the client accepts.
+func (c *Client) responseLimit() int64 {
+	if c.maxResponseSize > 0 {
+		return c.maxResponseSize
+	}
+	return defaultMaxResponseSize
+}
+
+// encodeRequestBody returns the request body to send and its Content-Encoding,
+// compressing it when gzip is enabled.
+func (c *Client) encodeRequestBody(body []byte) ([]byte, string, error) {
+	if !c.gzip || len(body) < gzipMinSize {
+		return body, "", nil
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return nil, "", fmt.Errorf("failed to compress request: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to compress request: %w", err)
+	}
+	return buf.Bytes(), "gzip", nil
+}
+
+// decodeResponse decodes the JSON body of a 200 response into out, streaming it
+// through a json.Decoder and stopping once the response limit is exceeded. Read
+// failures are returned as *transportError.
+func (c *Client) decodeResponse(resp *http.Response, out interface{}, desc string) error {
+	body, err := responseBody(resp)
+	if err != nil {
+		return &transportError{fmt.Errorf("failed to read %s body: %w", desc, err)}
+	}
+	limit := c.responseLimit()
+	reader := &limitedReader{r: body, remaining: limit}
+	if err := json.NewDecoder(reader).Decode(out); err != nil {
+		switch {
+		case reader.err == ErrResponseTooLarge:
+			return fmt.Errorf("%w: %s exceeds %d bytes", ErrResponseTooLarge, desc, limit)
+		case reader.err != nil:
+			return &transportError{fmt.Errorf("failed to read %s body: %w", desc, reader.err)}
+		default:
+			return fmt.Errorf("failed to unmarshal %s: %w", desc, err)
+		}
+	}
+	return nil
+}
+
+// readErrorBody reads the start of the body of a non-200 response, truncated to
+// maxErrorBodySize bytes.
+func readErrorBody(resp *http.Response) []byte {
+	body, err := responseBody(resp)
+	if err != nil {
+		return nil
+	}
+	data, _ := io.ReadAll(io.LimitReader(body, maxErrorBodySize+1))
+	if len(data) > maxErrorBodySize {
+		data = append(data[:maxErrorBodySize:maxErrorBodySize], "…[truncated]"...)
+	}
+	return data
+}
+
+// responseBody returns the body of resp, decompressed if the transport left it
+// gzip-encoded because the request asked for gzip explicitly.
+func responseBody(resp *http.Response) (io.Reader, error) {
+	if !resp.Uncompressed && strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return gzip.NewReader(resp.Body)
+	}
+	return resp.Body, nil
+}
+
+// closeBody discards a bounded amount of the unread body, so that the connection
+// can be reused, and closes it.
+func closeBody(resp *http.Response) {
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainSize))
+	resp.Body.Close()
+}
+
+// limitedReader reads from r until remaining bytes have been read, then fails with
+// ErrResponseTooLarge. It records the first read error other than io.EOF.
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+	err       error
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		l.err = ErrResponseTooLarge
+		return 0, l.err
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if err != nil && err != io.EOF && l.err == nil {
+		l.err = err
+	}
+	return n, err
+}
+
+// batchResponse is the response to a batch: an array of responses, or a single error
+// response when the node rejects the whole batch, for example one over its size limit.
+type batchResponse struct {
+	responses []*JSONRPCResponse
+	rejected  *JSONRPCResponse
+}
+
+// UnmarshalJSON decodes either form of batch response.
+func (b *batchResponse) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		b.rejected = new(JSONRPCResponse)
+		return json.Unmarshal(trimmed, b.rejected)
+	}
+	return json.Unmarshal(data, &b.responses)
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxResponseSize(t *testing.T) {
+	ctx := context.Background()
+	large := strings.Repeat("a", 10_000)
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		if bytes.HasPrefix(body, []byte("[")) {
+			var reqs []JSONRPCRequest
+			require.NoError(t, json.Unmarshal(body, &reqs))
+			responses := make([]*JSONRPCResponse, len(reqs))
+			for i, req := range reqs {
+				responses[i] = NewJSONRPCResponse(req.ID, large)
+			}
+			json.NewEncoder(w).Encode(responses)
+			return
+		}
+		var req JSONRPCRequest
+		require.NoError(t, json.Unmarshal(body, &req))
+		json.NewEncoder(w).Encode(NewJSONRPCResponse(req.ID, large))
+	}))
+	defer server.Close()
+
+	t.Run("within limit", func(t *testing.T) {
+		response, err := New(server.URL, WithMaxResponseSize(20_000)).SendRequest(ctx, "eth_call")
+		require.NoError(t, err)
+		assert.Equal(t, large, response.Result)
+	})
+
+	t.Run("single response over limit", func(t *testing.T) {
+		calls.Store(0)
+		c := New(server.URL, WithMaxResponseSize(5_000), WithRetry(fastRetry()))
+		_, err := c.SendRequest(ctx, "eth_call")
+		assert.ErrorIs(t, err, ErrResponseTooLarge)
+		assert.Equal(t, int32(1), calls.Load(), "oversized responses are not retried")
+	})
+
+	t.Run("batch over limit", func(t *testing.T) {
+		c := New(server.URL, WithMaxResponseSize(15_000))
+		_, err := c.SendBatch(ctx, NewBatchRequest().Add("eth_call").Add("eth_call"))
+		assert.ErrorIs(t, err, ErrResponseTooLarge)
+	})
+
+	t.Run("limit applies after decompression", func(t *testing.T) {
+		bomb := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "gzip")
+			zw := gzip.NewWriter(w)
+			zw.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"`))
+			zw.Write(bytes.Repeat([]byte("0"), 1<<20))
+			zw.Write([]byte(`"}`))
+			zw.Close()
+		}))
+		defer bomb.Close()
+
+		_, err := New(bomb.URL, WithMaxResponseSize(64*1024)).SendRequest(ctx, "eth_call")
+		assert.ErrorIs(t, err, ErrResponseTooLarge)
+	})
+
+	t.Run("WebSocket message over limit", func(t *testing.T) {
+		node := newFakeStreamNode(t, map[string]rpcHandler{"eth_call": result(large)})
+		c := New(newWSNode(t, node), WithMaxResponseSize(5_000))
+		defer c.Close()
+
+		_, err := c.SendRequest(ctx, "eth_call")
+		assert.ErrorIs(t, err, errConnectionLost)
+	})
+}
+
+func TestTruncatedErrorBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(strings.Repeat("x", 100_000)))
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL).SendRequest(context.Background(), "eth_blockNumber")
+	var httpErr *HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusBadGateway, httpErr.StatusCode)
+	assert.Equal(t, strings.Repeat("x", maxErrorBodySize)+"…[truncated]", httpErr.Body)
+	assert.Less(t, len(err.Error()), 2*maxErrorBodySize)
+}
+
+func TestGzip(t *testing.T) {
+	ctx := context.Background()
+
+	// newGzipServer echoes the first param of every request, compressing the response
+	// when the client accepts gzip, and records the Content-Encoding of each request.
+	newGzipServer := func(t *testing.T) (*httptest.Server, *atomic.Value) {
+		var encoding atomic.Value
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encoding.Store(r.Header.Get("Content-Encoding"))
+			var body io.Reader = r.Body
+			if r.Header.Get("Content-Encoding") == "gzip" {
+				zr, err := gzip.NewReader(r.Body)
+				require.NoError(t, err)
+				body = zr
+			}
+			var req JSONRPCRequest
+			require.NoError(t, json.NewDecoder(body).Decode(&req))
+
+			var out io.Writer = w
+			if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+				w.Header().Set("Content-Encoding", "gzip")
+				zw := gzip.NewWriter(w)
+				defer zw.Close()
+				out = zw
+			}
+			json.NewEncoder(out).Encode(NewJSONRPCResponse(req.ID, req.Params[0]))
+		}))
+		t.Cleanup(server.Close)
+		return server, &encoding
+	}
+
+	t.Run("compresses large requests", func(t *testing.T) {
+		server, encoding := newGzipServer(t)
+		c := New(server.URL, WithGzip())
+
+		large := strings.Repeat("b", 4096)
+		response, err := c.SendRequest(ctx, "echo", large)
+		require.NoError(t, err)
+		assert.Equal(t, large, response.Result)
+		assert.Equal(t, "gzip", encoding.Load())
+
+		response, err = c.SendRequest(ctx, "echo", "small")
+		require.NoError(t, err)
+		assert.Equal(t, "small", response.Result)
+		assert.Equal(t, "", encoding.Load(), "small requests are sent uncompressed")
+	})
+
+	t.Run("compressed responses without WithGzip", func(t *testing.T) {
+		server, encoding := newGzipServer(t)
+		response, err := New(server.URL).SendRequest(ctx, "echo", strings.Repeat("c", 4096))
+		require.NoError(t, err)
+		assert.Equal(t, strings.Repeat("c", 4096), response.Result)
+		assert.Equal(t, "", encoding.Load())
+	})
+}
@@ -0,0 +1,199 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy configures how the client retries requests that failed transiently:
+// transport errors, HTTP 408, 429 and 5xx responses, and JSON-RPC rate limit errors.
+// Other JSON-RPC errors are never retried. Zero fields take their defaults.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first.
+	// Defaults to 4.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry. Defaults to 200ms.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the exponential backoff. Defaults to 10s.
+	MaxBackoff time.Duration
+
+	// Multiplier is the growth factor of the backoff between attempts. Defaults to 2.
+	Multiplier float64
+
+	// Jitter is the fraction of each backoff that is randomized, between 0 and 1.
+	// Defaults to 0.2, so delays fall between 80% and 100% of the backoff.
+	Jitter float64
+
+	// MaxRetryAfter is the longest Retry-After delay the client will honor. If the
+	// node asks to wait longer, the request fails without further attempts.
+	// Defaults to 1 minute.
+	MaxRetryAfter time.Duration
+
+	// RetrySends enables retrying eth_sendRawTransaction and eth_sendRawTransactionSync.
+	// Retries resend the exact same signed bytes, which is always safe: the node either
+	// accepts the transaction once or reports it as already known. If a retried
+	// eth_sendRawTransaction is reported as already known, an earlier attempt reached
+	// the node and the send succeeds. Building a different transaction for the same
+	// nonce is never done by the client and is not safe to do blindly on error.
+	RetrySends bool
+
+	// OnRetry, if set, is called before waiting for each retry.
+	OnRetry func(RetryEvent)
+
+	// OnGiveUp, if set, is called when a request fails with a transient error and no
+	// further attempts are made.
+	OnGiveUp func(RetryEvent)
+}
+
+// RetryEvent describes a failed attempt of a retried request.
+type RetryEvent struct {
+	// Method is the JSON-RPC method, or "batch" for batch requests.
+	Method string
+
+	// Attempt is the number of the attempt that failed, starting at 1.
+	Attempt int
+
+	// Err is the error of the failed attempt.
+	Err error
+
+	// Delay is the wait before the next attempt. Zero for OnGiveUp.
+	Delay time.Duration
+}
+
+// DefaultRetryPolicy returns the default retry policy, which also retries sends.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{RetrySends: true}
+}
+
+// WithRetry enables retrying transient failures with exponential backoff.
+// If policy is nil, DefaultRetryPolicy is used.
+func WithRetry(policy *RetryPolicy) Option {
+	if policy == nil {
+		policy = DefaultRetryPolicy()
+	}
+	p := *policy
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 4
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = 200 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 10 * time.Second
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+	if p.Jitter <= 0 || p.Jitter > 1 {
+		p.Jitter = 0.2
+	}
+	if p.MaxRetryAfter <= 0 {
+		p.MaxRetryAfter = time.Minute
+	}
+	return func(c *Client) {
+		c.retry = &p
+	}
+}
+
+// transportError marks a failure to exchange the HTTP request, which is retryable.
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string { return e.err.Error() }
+func (e *transportError) Unwrap() error { return e.err }
+
+// retryable reports whether a failed attempt may be retried.
+func retryable(err error) bool {
+	var transportErr *transportError
+	if errors.As(err, &transportErr) {
+		return true
+	}
+	if errors.Is(err, ErrRateLimited) {
+		return true
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusRequestTimeout, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+	return false
+}
+
+// isSendMethod reports whether method broadcasts a raw transaction.
+func isSendMethod(method string) bool {
+	return method == methodSendRawTransaction || method == methodSendRawTransactionSync
+}
+
+// backoff returns the delay after the given failed attempt, and false if the
+// request should not be retried because the node asked to wait too long.
+func (p *RetryPolicy) backoff(attempt int, err error) (time.Duration, bool) {
+	backoff := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(attempt-1))
+	if backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+	delay := time.Duration(backoff * (1 - p.Jitter*rand.Float64()))
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.RetryAfter > 0 {
+		if httpErr.RetryAfter > p.MaxRetryAfter {
+			return 0, false
+		}
+		if httpErr.RetryAfter > delay {
+			delay = httpErr.RetryAfter
+		}
+	}
+	return delay, true
+}
+
+// withRetry runs attempt until it succeeds, fails permanently, or the retry policy is
+// exhausted. Without a retry policy, or for sends when the policy does not retry
+// sends, attempt runs once.
+func (c *Client) withRetry(ctx context.Context, method string, send bool, attempt func() error) error {
+	policy := c.retry
+	if policy == nil || (send && !policy.RetrySends) {
+		return attempt()
+	}
+
+	for n := 1; ; n++ {
+		err := attempt()
+		if err == nil || !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		event := RetryEvent{Method: method, Attempt: n, Err: err}
+		delay, ok := policy.backoff(n, err)
+		if deadline, hasDeadline := ctx.Deadline(); hasDeadline && time.Until(deadline) < delay {
+			ok = false
+		}
+		if !ok || n >= policy.MaxAttempts {
+			if policy.OnGiveUp != nil {
+				policy.OnGiveUp(event)
+			}
+			return err
+		}
+
+		event.Delay = delay
+		if policy.OnRetry != nil {
+			policy.OnRetry(event)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (retry aborted: %w)", err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempoxyz/tempo-go/pkg/transaction"
+)
+
+// fastRetry is a retry policy with negligible delays.
+func fastRetry() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+		RetrySends:     true,
+	}
+}
+
+// newFlakyServer returns a server that fails the first failures requests with the
+// given HTTP status and then responds with result.
+func newFlakyServer(t *testing.T, failures int32, status int, result interface{}) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req JSONRPCRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if calls.Add(1) <= failures {
+			w.WriteHeader(status)
+			w.Write([]byte("try again"))
+			return
+		}
+		json.NewEncoder(w).Encode(NewJSONRPCResponse(req.ID, result))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestWithRetry(t *testing.T) {
+	t.Run("retries transient HTTP errors", func(t *testing.T) {
+		server, calls := newFlakyServer(t, 2, http.StatusBadGateway, "0x10")
+
+		var events []RetryEvent
+		policy := fastRetry()
+		policy.OnRetry = func(e RetryEvent) { events = append(events, e) }
+
+		number, err := New(server.URL, WithRetry(policy)).GetBlockNumber(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(16), number)
+		assert.Equal(t, int32(3), calls.Load())
+
+		require.Len(t, events, 2)
+		assert.Equal(t, "eth_blockNumber", events[0].Method)
+		assert.Equal(t, 1, events[0].Attempt)
+		assert.Equal(t, 2, events[1].Attempt)
+		var httpErr *HTTPError
+		assert.True(t, errors.As(events[0].Err, &httpErr))
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		server, calls := newFlakyServer(t, 10, http.StatusServiceUnavailable, "0x10")
+
+		var gaveUp *RetryEvent
+		policy := fastRetry()
+		policy.OnGiveUp = func(e RetryEvent) { gaveUp = &e }
+
+		_, err := New(server.URL, WithRetry(policy)).GetBlockNumber(context.Background())
+		var httpErr *HTTPError
+		require.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+		assert.Equal(t, int32(3), calls.Load())
+		require.NotNil(t, gaveUp)
+		assert.Equal(t, 3, gaveUp.Attempt)
+	})
+
+	t.Run("does not retry permanent errors", func(t *testing.T) {
+		server, calls := newFlakyServer(t, 10, http.StatusUnauthorized, "0x10")
+
+		_, err := New(server.URL, WithRetry(fastRetry())).GetBlockNumber(context.Background())
+		require.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("without policy", func(t *testing.T) {
+		server, calls := newFlakyServer(t, 1, http.StatusBadGateway, "0x10")
+
+		_, err := New(server.URL).GetBlockNumber(context.Background())
+		require.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("retries transport errors", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req JSONRPCRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			if calls.Add(1) == 1 {
+				conn, _, err := w.(http.Hijacker).Hijack()
+				require.NoError(t, err)
+				conn.Close()
+				return
+			}
+			json.NewEncoder(w).Encode(NewJSONRPCResponse(req.ID, "0x1"))
+		}))
+		t.Cleanup(server.Close)
+
+		number, err := New(server.URL, WithRetry(fastRetry())).GetBlockNumber(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), number)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("retries JSON-RPC rate limits", func(t *testing.T) {
+		var calls atomic.Int32
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_blockNumber": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+				if calls.Add(1) == 1 {
+					return nil, &JSONRPCError{Code: -32005, Message: "limit exceeded"}
+				}
+				return "0x2", nil
+			},
+		})
+
+		number, err := New(server.URL, WithRetry(fastRetry())).GetBlockNumber(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), number)
+	})
+
+	t.Run("does not retry other JSON-RPC errors", func(t *testing.T) {
+		var calls atomic.Int32
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_blockNumber": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+				calls.Add(1)
+				return nil, &JSONRPCError{Code: -32601, Message: "method not found"}
+			},
+		})
+
+		_, err := New(server.URL, WithRetry(fastRetry())).GetBlockNumber(context.Background())
+		require.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var reqs []JSONRPCRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&reqs))
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusGatewayTimeout)
+				return
+			}
+			responses := make([]*JSONRPCResponse, len(reqs))
+			for i, req := range reqs {
+				responses[i] = NewJSONRPCResponse(req.ID, "0x1")
+			}
+			json.NewEncoder(w).Encode(responses)
+		}))
+		t.Cleanup(server.Close)
+
+		batch := NewBatchRequest().Add("eth_blockNumber").Add("eth_chainId")
+		responses, err := New(server.URL, WithRetry(fastRetry())).SendBatch(context.Background(), batch)
+		require.NoError(t, err)
+		assert.Len(t, responses, 2)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("context cancelled during backoff", func(t *testing.T) {
+		server, calls := newFlakyServer(t, 10, http.StatusServiceUnavailable, "0x10")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		policy := fastRetry()
+		policy.InitialBackoff = time.Hour
+		policy.MaxBackoff = time.Hour
+		policy.OnRetry = func(RetryEvent) { cancel() }
+
+		_, err := New(server.URL, WithRetry(policy)).GetBlockNumber(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		var httpErr *HTTPError
+		assert.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("gives up when backoff exceeds deadline", func(t *testing.T) {
+		server, calls := newFlakyServer(t, 10, http.StatusServiceUnavailable, "0x10")
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		policy := fastRetry()
+		policy.InitialBackoff = time.Hour
+		policy.MaxBackoff = time.Hour
+
+		_, err := New(server.URL, WithRetry(policy)).GetBlockNumber(ctx)
+		require.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func TestWithRetrySends(t *testing.T) {
+	tx := newSignedTx(t, transaction.ChainIDTempoTestnet)
+	serializedTx, err := transaction.Serialize(tx, nil)
+	require.NoError(t, err)
+	txHash, err := tx.Hash()
+	require.NoError(t, err)
+
+	// newSendServer fails the first send with a gateway error after the node has
+	// already accepted it, then reports the transaction as already known.
+	newSendServer := func(t *testing.T) (*httptest.Server, *atomic.Int32) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req JSONRPCRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, serializedTx, req.Params[0])
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			json.NewEncoder(w).Encode(NewJSONRPCErrorResponse(req.ID, -32000, "already known", nil))
+		}))
+		t.Cleanup(server.Close)
+		return server, &calls
+	}
+
+	t.Run("resends the same bytes", func(t *testing.T) {
+		server, calls := newSendServer(t)
+
+		hash, err := New(server.URL, WithRetry(fastRetry())).SendRawTransaction(context.Background(), serializedTx)
+		require.NoError(t, err)
+		assert.Equal(t, txHash.Hex(), hash)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("sends not retried", func(t *testing.T) {
+		server, calls := newSendServer(t)
+		policy := fastRetry()
+		policy.RetrySends = false
+
+		_, err := New(server.URL, WithRetry(policy)).SendRawTransaction(context.Background(), serializedTx)
+		require.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("already known on first attempt", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			methodSendRawTransaction: func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+				return nil, &JSONRPCError{Code: -32000, Message: "already known"}
+			},
+		})
+
+		_, err := New(server.URL, WithRetry(fastRetry())).SendRawTransaction(context.Background(), serializedTx)
+		assert.ErrorIs(t, err, ErrAlreadyKnown)
+	})
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	var c Client
+	WithRetry(&RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Jitter:         0.5,
+		MaxRetryAfter:  10 * time.Second,
+	})(&c)
+	policy := c.retry
+
+	for attempt, max := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		5: time.Second,
+	} {
+		for i := 0; i < 20; i++ {
+			delay, ok := policy.backoff(attempt, errors.New("boom"))
+			require.True(t, ok)
+			assert.LessOrEqual(t, delay, max)
+			assert.GreaterOrEqual(t, delay, max/2)
+		}
+	}
+
+	delay, ok := policy.backoff(1, &HTTPError{StatusCode: http.StatusTooManyRequests, RetryAfter: 3 * time.Second})
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, delay)
+
+	_, ok = policy.backoff(1, &HTTPError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Hour})
+	assert.False(t, ok)
+}
@@ -0,0 +1,337 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// RevertKind classifies decoded revert data.
+type RevertKind int
+
+const (
+	// RevertUnknown is revert data whose selector is not in the registry.
+	RevertUnknown RevertKind = iota
+
+	// RevertErrorString is a Solidity require/revert with a message, Error(string).
+	RevertErrorString
+
+	// RevertPanic is a Solidity panic, Panic(uint256).
+	RevertPanic
+
+	// RevertCustom is a custom error resolved from the registry.
+	RevertCustom
+)
+
+var (
+	errorStringSelector = [4]byte{0x08, 0xc3, 0x79, 0xa0}
+	panicSelector       = [4]byte{0x4e, 0x48, 0x7b, 0x71}
+)
+
+// panicMeanings describes the Solidity panic codes.
+var panicMeanings = map[uint64]string{
+	0x00: "generic compiler panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array encoding",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to uninitialized internal function",
+}
+
+// Revert is decoded revert data.
+type Revert struct {
+	Kind RevertKind
+
+	// Name is the error name, such as "Error", "Panic" or "InsufficientBalance".
+	// Empty for unknown errors.
+	Name string
+
+	// Reason is the message of an Error(string) revert.
+	Reason string
+
+	// PanicCode is the code of a Panic(uint256) revert.
+	PanicCode *big.Int
+
+	// Args are the decoded arguments of a custom error, in declaration order.
+	Args []interface{}
+
+	// ArgNames are the declared names of the custom error arguments.
+	ArgNames []string
+
+	// Data is the raw revert data.
+	Data []byte
+
+	// Message is the error reported by the node, such as "execution reverted" or
+	// "out of gas". Only set by TraceRevert.
+	Message string
+}
+
+// PanicMeaning returns a description of the panic code, or an empty string.
+func (r *Revert) PanicMeaning() string {
+	if r.PanicCode == nil || !r.PanicCode.IsUint64() {
+		return ""
+	}
+	return panicMeanings[r.PanicCode.Uint64()]
+}
+
+// String returns a human-readable description of the revert.
+func (r *Revert) String() string {
+	switch r.Kind {
+	case RevertErrorString:
+		return r.Reason
+	case RevertPanic:
+		meaning := r.PanicMeaning()
+		if meaning == "" {
+			meaning = "unknown panic"
+		}
+		return fmt.Sprintf("panic: %s (0x%x)", meaning, r.PanicCode)
+	case RevertCustom:
+		args := make([]string, len(r.Args))
+		for i, arg := range r.Args {
+			args[i] = fmt.Sprintf("%s: %s", r.ArgNames[i], formatRevertArg(arg))
+		}
+		return fmt.Sprintf("%s(%s)", r.Name, strings.Join(args, ", "))
+	default:
+		if len(r.Data) >= 4 {
+			return fmt.Sprintf("unknown error %s", hexutil.Encode(r.Data[:4]))
+		}
+		if len(r.Data) == 0 && r.Message != "" {
+			return r.Message
+		}
+		return fmt.Sprintf("invalid revert data %s", hexutil.Encode(r.Data))
+	}
+}
+
+func formatRevertArg(arg interface{}) string {
+	switch v := arg.(type) {
+	case common.Address:
+		return v.Hex()
+	case []byte:
+		return hexutil.Encode(v)
+	case [32]byte:
+		return hexutil.Encode(v[:])
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// ErrorRegistry resolves custom error selectors to ABI error definitions.
+// ErrorRegistry is safe for concurrent use.
+type ErrorRegistry struct {
+	mu     sync.RWMutex
+	errors map[[4]byte]abi.Error
+}
+
+// NewErrorRegistry creates an empty error registry. Error(string) and Panic(uint256)
+// are always decoded, even by an empty registry.
+func NewErrorRegistry() *ErrorRegistry {
+	return &ErrorRegistry{errors: make(map[[4]byte]abi.Error)}
+}
+
+// DefaultErrorRegistry is preloaded with the errors of TIP-20 tokens and the Tempo
+// precompiles. It is used by clients that do not set WithErrorRegistry.
+var DefaultErrorRegistry = newDefaultErrorRegistry()
+
+// tempoErrors are the custom errors of TIP-20 tokens and the Tempo precompiles.
+var tempoErrors = []string{
+	// TIP-20 tokens
+	"InsufficientBalance(uint256 available, uint256 required, address token)",
+	"InsufficientAllowance()",
+	"SupplyCapExceeded()",
+	"InvalidSupplyCap()",
+	"InvalidPayload()",
+	"StringTooLong()",
+	"PolicyForbids()",
+	"InvalidRecipient()",
+	"ContractPaused()",
+	"InvalidCurrency()",
+	"InvalidQuoteToken()",
+	"TransfersDisabled()",
+	"InvalidAmount()",
+	"Unauthorized()",
+	"ProtectedAddress()",
+	"InvalidToken()",
+
+	// Fee manager
+	"OnlyValidator()",
+	"OnlySystemContract()",
+	"InvalidFeeToken(address token)",
+	"PoolDoesNotExist()",
+	"InsufficientLiquidity()",
+	"InsufficientFeeTokenBalance()",
+	"CannotChangeWithinBlock()",
+
+	// Nonce manager
+	"ProtocolNonceNotSupported()",
+	"InvalidNonceKey()",
+	"NonceOverflow()",
+
+	// TIP-403 transfer policy registry
+	"PolicyNotFound()",
+	"IncompatiblePolicyType()",
+}
+
+func newDefaultErrorRegistry() *ErrorRegistry {
+	registry := NewErrorRegistry()
+	for _, signature := range tempoErrors {
+		if err := registry.Register(signature); err != nil {
+			panic(err)
+		}
+	}
+	return registry
+}
+
+// Register adds a custom error given as a Solidity signature with optional argument
+// names, such as "InsufficientBalance(uint256 available, uint256 required, address token)".
+// Tuple arguments are not supported; use RegisterABI for those.
+func (r *ErrorRegistry) Register(signature string) error {
+	open := strings.Index(signature, "(")
+	if open <= 0 || !strings.HasSuffix(signature, ")") {
+		return fmt.Errorf("invalid error signature %q", signature)
+	}
+	name := strings.TrimSpace(signature[:open])
+	params := strings.TrimSpace(signature[open+1 : len(signature)-1])
+
+	var inputs abi.Arguments
+	if params != "" {
+		for i, param := range strings.Split(params, ",") {
+			fields := strings.Fields(param)
+			if len(fields) == 0 || len(fields) > 2 {
+				return fmt.Errorf("invalid parameter %d in error signature %q", i, signature)
+			}
+			typ, err := abi.NewType(fields[0], "", nil)
+			if err != nil {
+				return fmt.Errorf("invalid parameter %d in error signature %q: %w", i, signature, err)
+			}
+			arg := abi.Argument{Type: typ}
+			if len(fields) == 2 {
+				arg.Name = fields[1]
+			}
+			inputs = append(inputs, arg)
+		}
+	}
+
+	r.add(abi.NewError(name, inputs))
+	return nil
+}
+
+// RegisterABI adds all custom errors of a parsed contract ABI.
+func (r *ErrorRegistry) RegisterABI(contract abi.ABI) {
+	for _, e := range contract.Errors {
+		r.add(e)
+	}
+}
+
+func (r *ErrorRegistry) add(e abi.Error) {
+	var selector [4]byte
+	copy(selector[:], e.ID[:4])
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors[selector] = e
+}
+
+// Decode decodes revert data. Returns nil if data is empty.
+// Data that cannot be decoded is returned as a RevertUnknown revert.
+func (r *ErrorRegistry) Decode(data []byte) *Revert {
+	if len(data) == 0 {
+		return nil
+	}
+	revert := &Revert{Kind: RevertUnknown, Data: data}
+	if len(data) < 4 {
+		return revert
+	}
+
+	var selector [4]byte
+	copy(selector[:], data[:4])
+	switch selector {
+	case errorStringSelector:
+		if reason, err := abi.UnpackRevert(data); err == nil {
+			revert.Kind = RevertErrorString
+			revert.Name = "Error"
+			revert.Reason = reason
+		}
+		return revert
+	case panicSelector:
+		if len(data) == 36 {
+			revert.Kind = RevertPanic
+			revert.Name = "Panic"
+			revert.PanicCode = new(big.Int).SetBytes(data[4:])
+		}
+		return revert
+	}
+
+	r.mu.RLock()
+	e, ok := r.errors[selector]
+	r.mu.RUnlock()
+	if !ok {
+		return revert
+	}
+	args, err := e.Inputs.Unpack(data[4:])
+	if err != nil {
+		return revert
+	}
+
+	revert.Kind = RevertCustom
+	revert.Name = e.Name
+	revert.Args = args
+	revert.ArgNames = make([]string, len(e.Inputs))
+	for i, input := range e.Inputs {
+		revert.ArgNames[i] = input.Name
+	}
+	return revert
+}
+
+// DecodeRevert decodes revert data with DefaultErrorRegistry.
+func DecodeRevert(data []byte) *Revert {
+	return DefaultErrorRegistry.Decode(data)
+}
+
+// callTrace is the part of a callTracer result needed to find the revert data.
+type callTrace struct {
+	Output hexutil.Bytes `json:"output"`
+	Error  string        `json:"error"`
+}
+
+// TraceRevert re-executes an included transaction with debug_traceTransaction and
+// decodes its revert data. Use it on receipts that did not succeed, since receipts
+// do not carry revert data. Returns nil if the transaction did not revert.
+// The node must expose the debug namespace.
+func (c *Client) TraceRevert(ctx context.Context, txHash common.Hash) (*Revert, error) {
+	var trace *callTrace
+	tracer := map[string]interface{}{"tracer": "callTracer"}
+	if err := c.call(ctx, &trace, "debug_traceTransaction", txHash, tracer); err != nil {
+		return nil, err
+	}
+	if trace == nil {
+		return nil, fmt.Errorf("%w: trace of %s", ErrNotFound, txHash.Hex())
+	}
+	if trace.Error == "" {
+		return nil, nil
+	}
+	revert := &Revert{Kind: RevertUnknown}
+	if len(trace.Output) > 0 {
+		revert = c.errorRegistry().Decode(trace.Output)
+	}
+	// Reverts without data, such as running out of gas, only carry the message.
+	revert.Message = trace.Error
+	return revert, nil
+}
+
+// errorRegistry returns the registry used to decode revert data.
+func (c *Client) errorRegistry() *ErrorRegistry {
+	if c.errorReg != nil {
+		return c.errorReg
+	}
+	return DefaultErrorRegistry
+}
@@ -0,0 +1,237 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempoxyz/tempo-go/pkg/transaction"
+)
+
+// insufficientBalanceData is the ABI encoding of
+// InsufficientBalance(5, 10, 0x20c0000000000000000000000000000000000001).
+var insufficientBalanceData = hexutil.MustDecode(
+	hexutil.Encode(selector("InsufficientBalance(uint256,uint256,address)")) +
+		"0000000000000000000000000000000000000000000000000000000000000005" +
+		"000000000000000000000000000000000000000000000000000000000000000a" +
+		"00000000000000000000000020c0000000000000000000000000000000000001")
+
+// selector returns the 4-byte selector of an error signature.
+func selector(signature string) []byte {
+	return crypto.Keccak256([]byte(signature))[:4]
+}
+
+func TestDecodeRevert(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		kind   RevertKind
+		want   string
+		reason string
+	}{
+		{
+			name:   "error string",
+			data:   revertData,
+			kind:   RevertErrorString,
+			want:   "insufficient balance",
+			reason: "insufficient balance",
+		},
+		{
+			name: "panic",
+			data: "0x4e487b71" + "0000000000000000000000000000000000000000000000000000000000000011",
+			kind: RevertPanic,
+			want: "panic: arithmetic underflow or overflow (0x11)",
+		},
+		{
+			name: "unknown panic code",
+			data: "0x4e487b71" + "00000000000000000000000000000000000000000000000000000000000000ff",
+			kind: RevertPanic,
+			want: "panic: unknown panic (0xff)",
+		},
+		{
+			name: "custom error",
+			data: hexutil.Encode(insufficientBalanceData),
+			kind: RevertCustom,
+			want: "InsufficientBalance(available: 5, required: 10, token: 0x20C0000000000000000000000000000000000001)",
+		},
+		{
+			name: "custom error without arguments",
+			data: hexutil.Encode(selector("Unauthorized()")),
+			kind: RevertCustom,
+			want: "Unauthorized()",
+		},
+		{
+			name: "unknown selector",
+			data: "0xdeadbeef",
+			kind: RevertUnknown,
+			want: "unknown error 0xdeadbeef",
+		},
+		{
+			name: "malformed custom error",
+			data: hexutil.Encode(insufficientBalanceData[:40]),
+			kind: RevertUnknown,
+			want: "unknown error " + hexutil.Encode(insufficientBalanceData[:4]),
+		},
+		{
+			name: "short data",
+			data: "0x01",
+			kind: RevertUnknown,
+			want: "invalid revert data 0x01",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revert := DecodeRevert(hexutil.MustDecode(tt.data))
+			require.NotNil(t, revert)
+			assert.Equal(t, tt.kind, revert.Kind)
+			assert.Equal(t, tt.want, revert.String())
+			assert.Equal(t, tt.reason, revert.Reason)
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		assert.Nil(t, DecodeRevert(nil))
+	})
+
+	t.Run("custom error arguments", func(t *testing.T) {
+		revert := DecodeRevert(insufficientBalanceData)
+		require.NotNil(t, revert)
+		assert.Equal(t, "InsufficientBalance", revert.Name)
+		assert.Equal(t, []string{"available", "required", "token"}, revert.ArgNames)
+		require.Len(t, revert.Args, 3)
+		assert.Equal(t, big.NewInt(5), revert.Args[0])
+		assert.Equal(t, common.HexToAddress("0x20c0000000000000000000000000000000000001"), revert.Args[2])
+	})
+}
+
+func TestErrorRegistry(t *testing.T) {
+	t.Run("register signature", func(t *testing.T) {
+		registry := NewErrorRegistry()
+		data := append(selector("Expired(uint64)"), common.LeftPadBytes([]byte{0x64}, 32)...)
+
+		assert.Equal(t, RevertUnknown, registry.Decode(data).Kind)
+
+		require.NoError(t, registry.Register("Expired(uint64 deadline)"))
+		revert := registry.Decode(data)
+		assert.Equal(t, RevertCustom, revert.Kind)
+		assert.Equal(t, "Expired(deadline: 100)", revert.String())
+	})
+
+	t.Run("register ABI", func(t *testing.T) {
+		contract, err := abi.JSON(strings.NewReader(`[{"type":"error","name":"Blocked","inputs":[{"name":"account","type":"address"}]}]`))
+		require.NoError(t, err)
+
+		registry := NewErrorRegistry()
+		registry.RegisterABI(contract)
+
+		data := append(selector("Blocked(address)"), common.LeftPadBytes(testAccount.Bytes(), 32)...)
+		revert := registry.Decode(data)
+		assert.Equal(t, "Blocked", revert.Name)
+		assert.Equal(t, []interface{}{testAccount}, revert.Args)
+	})
+
+	t.Run("builtin errors with empty registry", func(t *testing.T) {
+		revert := NewErrorRegistry().Decode(hexutil.MustDecode(revertData))
+		assert.Equal(t, RevertErrorString, revert.Kind)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		registry := NewErrorRegistry()
+		assert.Error(t, registry.Register("Broken"))
+		assert.Error(t, registry.Register("Broken(notatype)"))
+		assert.Error(t, registry.Register("Broken(uint256 a b)"))
+	})
+}
+
+func TestRevertErrorCustomError(t *testing.T) {
+	server := newRPCServer(t, map[string]rpcHandler{
+		"eth_estimateGas": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+			return nil, &JSONRPCError{Code: 3, Message: "execution reverted", Data: hexutil.Encode(insufficientBalanceData)}
+		},
+	})
+
+	t.Run("default registry", func(t *testing.T) {
+		_, err := New(server.URL).EstimateGas(context.Background(), transaction.New())
+
+		var revertErr *RevertError
+		require.True(t, errors.As(err, &revertErr))
+		require.NotNil(t, revertErr.Revert)
+		assert.Equal(t, "InsufficientBalance", revertErr.Revert.Name)
+		assert.Equal(t, "execution reverted: "+revertErr.Revert.String(), revertErr.Error())
+		assert.True(t, errors.Is(err, ErrExecutionReverted))
+	})
+
+	t.Run("custom registry", func(t *testing.T) {
+		_, err := New(server.URL, WithErrorRegistry(NewErrorRegistry())).EstimateGas(context.Background(), transaction.New())
+
+		var revertErr *RevertError
+		require.True(t, errors.As(err, &revertErr))
+		assert.Equal(t, RevertUnknown, revertErr.Revert.Kind)
+		assert.Empty(t, revertErr.Reason)
+		assert.Equal(t, "execution reverted with data "+hexutil.Encode(insufficientBalanceData), revertErr.Error())
+	})
+}
+
+func TestTraceRevert(t *testing.T) {
+	txHash := common.HexToHash("0xabc")
+
+	t.Run("reverted", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"debug_traceTransaction": func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError) {
+				assert.Equal(t, txHash.Hex(), req.Params[0])
+				assert.Equal(t, map[string]interface{}{"tracer": "callTracer"}, req.Params[1])
+				return map[string]interface{}{
+					"error":  "execution reverted",
+					"output": hexutil.Encode(insufficientBalanceData),
+				}, nil
+			},
+		})
+
+		revert, err := New(server.URL).TraceRevert(context.Background(), txHash)
+		require.NoError(t, err)
+		require.NotNil(t, revert)
+		assert.Equal(t, "InsufficientBalance", revert.Name)
+		assert.Equal(t, "execution reverted", revert.Message)
+	})
+
+	t.Run("reverted without data", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"debug_traceTransaction": result(map[string]interface{}{"error": "out of gas", "output": "0x"}),
+		})
+
+		revert, err := New(server.URL).TraceRevert(context.Background(), txHash)
+		require.NoError(t, err)
+		require.NotNil(t, revert)
+		assert.Equal(t, RevertUnknown, revert.Kind)
+		assert.Equal(t, "out of gas", revert.Message)
+		assert.Equal(t, "out of gas", revert.String())
+	})
+
+	t.Run("succeeded", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"debug_traceTransaction": result(map[string]interface{}{"output": "0x"}),
+		})
+
+		revert, err := New(server.URL).TraceRevert(context.Background(), txHash)
+		require.NoError(t, err)
+		assert.Nil(t, revert)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"debug_traceTransaction": result(nil),
+		})
+
+		_, err := New(server.URL).TraceRevert(context.Background(), txHash)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// balanceOfSelector is the function selector of TIP-20 balanceOf(address).
+var balanceOfSelector = []byte{0x70, 0xa0, 0x82, 0x31}
+
+// GetBalance gets the native balance of an account at the given block.
+func (c *Client) GetBalance(ctx context.Context, address common.Address, block BlockSelector) (*big.Int, error) {
+	var balance hexutil.Big
+	if err := c.call(ctx, &balance, "eth_getBalance", address, block); err != nil {
+		return nil, err
+	}
+	return balance.ToInt(), nil
+}
+
+// GetCode gets the contract code deployed at an address at the given block.
+// Returns empty code for accounts without code.
+func (c *Client) GetCode(ctx context.Context, address common.Address, block BlockSelector) ([]byte, error) {
+	var code hexutil.Bytes
+	if err := c.call(ctx, &code, "eth_getCode", address, block); err != nil {
+		return nil, err
+	}
+	return code, nil
+}
+
+// GetStorageAt gets the value of a storage slot of an account at the given block.
+func (c *Client) GetStorageAt(ctx context.Context, address common.Address, slot common.Hash, block BlockSelector) (common.Hash, error) {
+	// Some nodes strip leading zeros from the value, so parse it leniently.
+	var value string
+	if err := c.call(ctx, &value, "eth_getStorageAt", address, slot, block); err != nil {
+		return common.Hash{}, err
+	}
+	return common.HexToHash(value), nil
+}
+
+// AccountProof is the Merkle proof of an account and some of its storage slots, as
+// returned by eth_getProof (EIP-1186).
+type AccountProof struct {
+	Address      common.Address
+	AccountProof [][]byte
+	Balance      *big.Int
+	CodeHash     common.Hash
+	Nonce        uint64
+	StorageHash  common.Hash
+	StorageProof []StorageProof
+}
+
+// StorageProof is the Merkle proof of a single storage slot.
+type StorageProof struct {
+	Key   common.Hash
+	Value *big.Int
+	Proof [][]byte
+}
+
+type accountProofJSON struct {
+	Address      common.Address     `json:"address"`
+	AccountProof []hexutil.Bytes    `json:"accountProof"`
+	Balance      *hexutil.Big       `json:"balance"`
+	CodeHash     common.Hash        `json:"codeHash"`
+	Nonce        hexutil.Uint64     `json:"nonce"`
+	StorageHash  common.Hash        `json:"storageHash"`
+	StorageProof []storageProofJSON `json:"storageProof"`
+}
+
+type storageProofJSON struct {
+	Key   string          `json:"key"`
+	Value *hexutil.Big    `json:"value"`
+	Proof []hexutil.Bytes `json:"proof"`
+}
+
+// UnmarshalJSON decodes an account proof from its JSON-RPC representation.
+func (p *AccountProof) UnmarshalJSON(data []byte) error {
+	var dec accountProofJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+
+	*p = AccountProof{
+		Address:      dec.Address,
+		AccountProof: toByteSlices(dec.AccountProof),
+		Balance:      (*big.Int)(dec.Balance),
+		CodeHash:     dec.CodeHash,
+		Nonce:        uint64(dec.Nonce),
+		StorageHash:  dec.StorageHash,
+		StorageProof: make([]StorageProof, 0, len(dec.StorageProof)),
+	}
+	for _, sp := range dec.StorageProof {
+		p.StorageProof = append(p.StorageProof, StorageProof{
+			// Keys are echoed as requested and may be returned without leading zeros.
+			Key:   common.HexToHash(sp.Key),
+			Value: (*big.Int)(sp.Value),
+			Proof: toByteSlices(sp.Proof),
+		})
+	}
+	return nil
+}
+
+func toByteSlices(in []hexutil.Bytes) [][]byte {
+	out := make([][]byte, len(in))
+	for i, b := range in {
+		out[i] = b
+	}
+	return out
+}
+
+// GetProof gets the Merkle proof of an account and the given storage slots at the given block.
+func (c *Client) GetProof(ctx context.Context, address common.Address, slots []common.Hash, block BlockSelector) (*AccountProof, error) {
+	if slots == nil {
+		slots = []common.Hash{}
+	}
+
+	var proof *AccountProof
+	if err := c.call(ctx, &proof, "eth_getProof", address, slots, block); err != nil {
+		return nil, err
+	}
+	if proof == nil {
+		return nil, fmt.Errorf("%w: proof for %s", ErrNotFound, address.Hex())
+	}
+	return proof, nil
+}
+
+// TokenBalance gets the balance of a TIP-20 token (such as transaction.AlphaUSDAddress)
+// held by holder at the latest block, in the token's base units.
+func (c *Client) TokenBalance(ctx context.Context, token, holder common.Address) (*big.Int, error) {
+	data := make([]byte, 0, 36)
+	data = append(data, balanceOfSelector...)
+	data = append(data, common.LeftPadBytes(holder.Bytes(), 32)...)
+
+	output, err := c.callContract(ctx, token, data, Latest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s balance of %s: %w", token.Hex(), holder.Hex(), err)
+	}
+	if len(output) != 32 {
+		return nil, fmt.Errorf("unexpected balanceOf result length %d from %s", len(output), token.Hex())
+	}
+	return new(big.Int).SetBytes(output), nil
+}
+
+// callContract executes a read-only eth_call against a single contract.
+func (c *Client) callContract(ctx context.Context, to common.Address, data []byte, block BlockSelector) ([]byte, error) {
+	msg := map[string]interface{}{
+		"to":   to,
+		"data": hexutil.Bytes(data),
+	}
+
+	var output hexutil.Bytes
+	if err := c.call(ctx, &output, "eth_call", msg, block); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
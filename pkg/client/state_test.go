@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempoxyz/tempo-go/pkg/transaction"
+)
+
+var testAccount = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+
+func TestGetBalance(t *testing.T) {
+	server := newRPCServer(t, map[string]rpcHandler{
+		"eth_getBalance": func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError) {
+			assert.Equal(t, []interface{}{"0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266", "finalized"}, req.Params)
+			return "0x21e19e0c9bab2400000", nil
+		},
+	})
+
+	balance, err := New(server.URL).GetBalance(context.Background(), testAccount, Finalized)
+	require.NoError(t, err)
+
+	expected, _ := new(big.Int).SetString("10000000000000000000000", 10)
+	assert.Equal(t, expected, balance)
+}
+
+func TestGetCode(t *testing.T) {
+	server := newRPCServer(t, map[string]rpcHandler{
+		"eth_getCode": func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError) {
+			assert.Equal(t, "0x10", req.Params[1])
+			return "0x6080604052", nil
+		},
+	})
+
+	code, err := New(server.URL).GetCode(context.Background(), testAccount, AtBlockNumber(16))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x60, 0x80, 0x60, 0x40, 0x52}, code)
+}
+
+func TestGetStorageAt(t *testing.T) {
+	slot := common.HexToHash("0x02")
+	blockHash := common.HexToHash("0x2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0")
+
+	server := newRPCServer(t, map[string]rpcHandler{
+		"eth_getStorageAt": func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError) {
+			assert.Equal(t, slot.Hex(), req.Params[1])
+			assert.Equal(t, map[string]interface{}{"blockHash": blockHash.Hex()}, req.Params[2])
+			return "0x000000000000000000000000000000000000000000000000000000000000002a", nil
+		},
+	})
+
+	value, err := New(server.URL).GetStorageAt(context.Background(), testAccount, slot, AtBlockHash(blockHash))
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToHash("0x2a"), value)
+}
+
+func TestGetProof(t *testing.T) {
+	server := newRPCServer(t, map[string]rpcHandler{
+		"eth_getProof": func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError) {
+			assert.Equal(t, []interface{}{common.HexToHash("0x01").Hex()}, req.Params[1])
+			return map[string]interface{}{
+				"address":      "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266",
+				"accountProof": []interface{}{"0xf90211a0", "0xf8718080"},
+				"balance":      "0x64",
+				"codeHash":     "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
+				"nonce":        "0x3",
+				"storageHash":  "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421",
+				"storageProof": []interface{}{
+					map[string]interface{}{
+						"key":   "0x1",
+						"value": "0x2a",
+						"proof": []interface{}{"0xe3a120"},
+					},
+				},
+			}, nil
+		},
+	})
+
+	proof, err := New(server.URL).GetProof(context.Background(), testAccount, []common.Hash{common.HexToHash("0x01")}, Latest)
+	require.NoError(t, err)
+
+	assert.Equal(t, testAccount, proof.Address)
+	assert.Len(t, proof.AccountProof, 2)
+	assert.Equal(t, big.NewInt(100), proof.Balance)
+	assert.Equal(t, uint64(3), proof.Nonce)
+	require.Len(t, proof.StorageProof, 1)
+	assert.Equal(t, common.HexToHash("0x01"), proof.StorageProof[0].Key)
+	assert.Equal(t, big.NewInt(42), proof.StorageProof[0].Value)
+}
+
+func TestTokenBalance(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_call": func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError) {
+				msg := req.Params[0].(map[string]interface{})
+				assert.Equal(t, "0x20c0000000000000000000000000000000000001", msg["to"])
+				assert.Equal(t, "0x70a08231000000000000000000000000f39fd6e51aad88f6f4ce6ab8827279cfffb92266", msg["data"])
+				assert.Equal(t, "latest", req.Params[1])
+				return "0x00000000000000000000000000000000000000000000000000000000000f4240", nil
+			},
+		})
+
+		balance, err := New(server.URL).TokenBalance(context.Background(), transaction.AlphaUSDAddress, testAccount)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(1000000), balance)
+	})
+
+	t.Run("not a token", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_call": result("0x"),
+		})
+
+		_, err := New(server.URL).TokenBalance(context.Background(), testAccount, testAccount)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unexpected balanceOf result length")
+	})
+}
@@ -0,0 +1,481 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// subscriptionBuffer is how many notifications are queued per subscription before
+	// the subscription is dropped for being too slow.
+	subscriptionBuffer = 4096
+
+	// reconnectMinBackoff and reconnectMaxBackoff bound the delay between reconnects.
+	reconnectMinBackoff = 100 * time.Millisecond
+	reconnectMaxBackoff = 10 * time.Second
+
+	// unsubscribeTimeout bounds the eth_unsubscribe request sent on cancellation.
+	unsubscribeTimeout = 5 * time.Second
+)
+
+var (
+	// errStreamClosed is returned for requests on a closed client.
+	errStreamClosed = errors.New("client closed")
+
+	// errConnectionLost is returned for requests that were in flight when the
+	// connection dropped.
+	errConnectionLost = errors.New("connection lost")
+
+	// errCallHeaders is returned for calls with headers over WebSocket or IPC, which
+	// only send headers with the handshake.
+	errCallHeaders = errors.New("call headers are only supported over HTTP")
+)
+
+// streamCodec exchanges JSON-RPC messages over a persistent connection.
+// writeMessage is never called concurrently.
+type streamCodec interface {
+	writeMessage(data []byte) error
+	readMessage() ([]byte, error)
+	close() error
+}
+
+// pendingCall is a request awaiting its response.
+type pendingCall struct {
+	ch chan *JSONRPCResponse
+
+	// onResponse, if set, runs in the read loop before later messages are handled.
+	onResponse func(*JSONRPCResponse)
+}
+
+// streamTransport multiplexes JSON-RPC calls and subscriptions over a persistent
+// connection such as a WebSocket or a Unix socket. Every request is sent with a
+// fresh ID and its response is matched back by ID, so calls may complete out of
+// order. The connection is dialed on first use and redialed after it drops;
+// subscriptions are then re-established in the background.
+type streamTransport struct {
+	dial func(ctx context.Context) (streamCodec, error)
+
+	nextID atomic.Uint64
+
+	// dialMu serializes dialing.
+	dialMu sync.Mutex
+
+	// writeMu serializes writes to the connection.
+	writeMu sync.Mutex
+
+	mu           sync.Mutex
+	conn         streamCodec
+	pending      map[uint64]*pendingCall
+	subs         map[string]*subscription // by server subscription ID
+	active       map[*subscription]bool
+	reconnecting bool
+	closed       bool
+}
+
+func newStreamTransport(dial func(ctx context.Context) (streamCodec, error)) *streamTransport {
+	return &streamTransport{
+		dial:    dial,
+		pending: make(map[uint64]*pendingCall),
+		subs:    make(map[string]*subscription),
+		active:  make(map[*subscription]bool),
+	}
+}
+
+// connect returns the current connection, dialing a new one if needed.
+func (t *streamTransport) connect(ctx context.Context) (streamCodec, error) {
+	t.dialMu.Lock()
+	defer t.dialMu.Unlock()
+
+	t.mu.Lock()
+	conn, closed := t.conn, t.closed
+	t.mu.Unlock()
+	if closed {
+		return nil, errStreamClosed
+	}
+	if conn != nil {
+		return conn, nil
+	}
+
+	conn, err := t.dial(ctx)
+	if err != nil {
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) {
+			return nil, fmt.Errorf("failed to connect: %w", err)
+		}
+		return nil, &transportError{fmt.Errorf("failed to connect: %w", err)}
+	}
+
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		conn.close()
+		return nil, errStreamClosed
+	}
+	t.conn = conn
+	t.mu.Unlock()
+
+	go t.readLoop(conn)
+	return conn, nil
+}
+
+// call sends a single request or a batch and waits for every response.
+// The responses carry the IDs of the original requests. Calls with headers fail,
+// since messages on an open connection cannot carry any.
+func (t *streamTransport) call(ctx context.Context, call *Invocation) ([]*JSONRPCResponse, error) {
+	if len(call.Header) > 0 {
+		return nil, errCallHeaders
+	}
+	return t.request(ctx, call.Requests, call.Batch, nil)
+}
+
+// request sends requests with fresh IDs and waits for their responses. onResponse is
+// only supported for single requests.
+func (t *streamTransport) request(ctx context.Context, requests []*JSONRPCRequest, batch bool, onResponse func(*JSONRPCResponse)) ([]*JSONRPCResponse, error) {
+	conn, err := t.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	wire := make([]*JSONRPCRequest, len(requests))
+	ids := make([]uint64, len(requests))
+	calls := make([]*pendingCall, len(requests))
+	for i, request := range requests {
+		ids[i] = t.nextID.Add(1)
+		wire[i] = NewJSONRPCRequest(ids[i], request.Method, request.Params...)
+		calls[i] = &pendingCall{ch: make(chan *JSONRPCResponse, 1), onResponse: onResponse}
+	}
+
+	var body []byte
+	if batch {
+		body, err = json.Marshal(wire)
+	} else {
+		body, err = json.Marshal(wire[0])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	t.mu.Lock()
+	if t.conn != conn {
+		t.mu.Unlock()
+		return nil, &transportError{errConnectionLost}
+	}
+	for i, id := range ids {
+		t.pending[id] = calls[i]
+	}
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		for _, id := range ids {
+			delete(t.pending, id)
+		}
+		t.mu.Unlock()
+	}()
+
+	t.writeMu.Lock()
+	err = conn.writeMessage(body)
+	t.writeMu.Unlock()
+	if err != nil {
+		t.disconnect(conn)
+		return nil, &transportError{fmt.Errorf("failed to send request: %w", err)}
+	}
+
+	responses := make([]*JSONRPCResponse, len(requests))
+	for i, pending := range calls {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case response, ok := <-pending.ch:
+			if !ok {
+				return nil, &transportError{errConnectionLost}
+			}
+			response.ID = requests[i].ID
+			responses[i] = response
+		}
+	}
+	return responses, nil
+}
+
+// streamMessage is the envelope of a message received from the node.
+type streamMessage struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params *struct {
+		Subscription string          `json:"subscription"`
+		Result       json.RawMessage `json:"result"`
+	} `json:"params"`
+}
+
+func (t *streamTransport) readLoop(conn streamCodec) {
+	for {
+		data, err := conn.readMessage()
+		if err != nil {
+			t.disconnect(conn)
+			return
+		}
+		data = bytes.TrimSpace(data)
+		if len(data) > 0 && data[0] == '[' {
+			var batch []json.RawMessage
+			if err := json.Unmarshal(data, &batch); err != nil {
+				continue
+			}
+			for _, message := range batch {
+				t.dispatch(message)
+			}
+			continue
+		}
+		t.dispatch(data)
+	}
+}
+
+// dispatch routes a response to its pending call and a notification to its subscription.
+// Malformed messages and responses to unknown IDs are dropped.
+func (t *streamTransport) dispatch(data []byte) {
+	var message streamMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return
+	}
+
+	if message.Method == "eth_subscription" && message.Params != nil {
+		t.mu.Lock()
+		sub := t.subs[message.Params.Subscription]
+		t.mu.Unlock()
+		if sub == nil {
+			return
+		}
+		select {
+		case sub.notifications <- message.Params.Result:
+		default:
+			t.endSubscription(sub, errors.New("subscription dropped: notifications are not being consumed"))
+			go t.unsubscribe(sub)
+		}
+		return
+	}
+
+	id, err := strconv.ParseUint(string(message.ID), 10, 64)
+	if err != nil {
+		return
+	}
+	var response JSONRPCResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	pending := t.pending[id]
+	delete(t.pending, id)
+	t.mu.Unlock()
+	if pending == nil {
+		return
+	}
+	if pending.onResponse != nil {
+		pending.onResponse(&response)
+	}
+	pending.ch <- &response
+}
+
+// disconnect tears down a connection, fails its pending calls and starts
+// re-establishing subscriptions.
+func (t *streamTransport) disconnect(conn streamCodec) {
+	t.mu.Lock()
+	if t.conn != conn {
+		t.mu.Unlock()
+		return
+	}
+	t.conn = nil
+	for id, pending := range t.pending {
+		close(pending.ch)
+		delete(t.pending, id)
+	}
+	for id := range t.subs {
+		delete(t.subs, id)
+	}
+	resubscribe := len(t.active) > 0 && !t.reconnecting && !t.closed
+	if resubscribe {
+		t.reconnecting = true
+	}
+	t.mu.Unlock()
+
+	conn.close()
+	if resubscribe {
+		go t.reconnectLoop()
+	}
+}
+
+// reconnectLoop redials with exponential backoff until every active subscription is
+// re-established or the transport is closed.
+func (t *streamTransport) reconnectLoop() {
+	backoff := reconnectMinBackoff
+	for {
+		t.mu.Lock()
+		if t.closed || len(t.active) == 0 {
+			t.reconnecting = false
+			t.mu.Unlock()
+			return
+		}
+		subs := make([]*subscription, 0, len(t.active))
+		for sub := range t.active {
+			subs = append(subs, sub)
+		}
+		t.mu.Unlock()
+
+		if t.resubscribeAll(subs) {
+			t.mu.Lock()
+			t.reconnecting = false
+			t.mu.Unlock()
+			return
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > reconnectMaxBackoff {
+			backoff = reconnectMaxBackoff
+		}
+	}
+}
+
+// resubscribeAll re-establishes subscriptions on a fresh connection. It returns false
+// if the connection failed and should be retried. Subscriptions rejected by the node
+// are ended with the node's error.
+func (t *streamTransport) resubscribeAll(subs []*subscription) bool {
+	for _, sub := range subs {
+		ctx, cancel := context.WithTimeout(context.Background(), unsubscribeTimeout)
+		err := t.sendSubscribe(ctx, sub)
+		cancel()
+
+		var rpcErr *JSONRPCError
+		switch {
+		case err == nil:
+		case errors.As(err, &rpcErr):
+			t.endSubscription(sub, err)
+		case errors.Is(err, errStreamClosed):
+			return true
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// subscribe starts a subscription with the given eth_subscribe params.
+func (t *streamTransport) subscribe(ctx context.Context, params []interface{}) (*subscription, error) {
+	sub := &subscription{
+		params:        params,
+		notifications: make(chan json.RawMessage, subscriptionBuffer),
+		done:          make(chan struct{}),
+	}
+	if err := t.sendSubscribe(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// sendSubscribe sends eth_subscribe for sub. The subscription is registered in the read
+// loop as soon as the response arrives, so no notification that follows it is missed.
+func (t *streamTransport) sendSubscribe(ctx context.Context, sub *subscription) error {
+	request := NewJSONRPCRequest(nil, "eth_subscribe", sub.params...)
+	responses, err := t.request(ctx, []*JSONRPCRequest{request}, false, func(response *JSONRPCResponse) {
+		id, ok := response.Result.(string)
+		if response.Error != nil || !ok {
+			return
+		}
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if sub.ended() {
+			return
+		}
+		sub.id = id
+		t.subs[id] = sub
+		t.active[sub] = true
+	})
+	if err != nil {
+		return err
+	}
+	if err := responses[0].CheckError(); err != nil {
+		return fmt.Errorf("eth_subscribe: %w", err)
+	}
+	if _, ok := responses[0].Result.(string); !ok {
+		return fmt.Errorf("unexpected subscription ID type: %T", responses[0].Result)
+	}
+	return nil
+}
+
+// unsubscribe ends sub and asks the node to stop sending its notifications.
+func (t *streamTransport) unsubscribe(sub *subscription) {
+	t.endSubscription(sub, nil)
+
+	t.mu.Lock()
+	id, connected := sub.id, t.conn != nil
+	t.mu.Unlock()
+	if !connected || id == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), unsubscribeTimeout)
+	defer cancel()
+	t.request(ctx, []*JSONRPCRequest{NewJSONRPCRequest(nil, "eth_unsubscribe", id)}, false, nil)
+}
+
+// endSubscription removes sub and records why it ended.
+func (t *streamTransport) endSubscription(sub *subscription, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if sub.ended() {
+		return
+	}
+	delete(t.active, sub)
+	if t.subs[sub.id] == sub {
+		delete(t.subs, sub.id)
+	}
+	sub.err = err
+	close(sub.done)
+}
+
+// close closes the connection and ends every subscription.
+func (t *streamTransport) close() {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return
+	}
+	t.closed = true
+	conn := t.conn
+	subs := make([]*subscription, 0, len(t.active))
+	for sub := range t.active {
+		subs = append(subs, sub)
+	}
+	t.mu.Unlock()
+
+	for _, sub := range subs {
+		t.endSubscription(sub, errStreamClosed)
+	}
+	if conn != nil {
+		t.disconnect(conn)
+	}
+}
+
+// subscription is the transport state of an eth_subscribe subscription.
+// id and err are guarded by the transport's mutex.
+type subscription struct {
+	params        []interface{}
+	id            string
+	notifications chan json.RawMessage
+	done          chan struct{}
+	err           error
+}
+
+func (s *subscription) ended() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,179 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStreamConn is a server-side connection of a fakeStreamNode.
+type fakeStreamConn interface {
+	send(data []byte) error
+	close()
+}
+
+// fakeStreamSub is a subscription held by a fakeStreamNode.
+type fakeStreamSub struct {
+	conn   fakeStreamConn
+	params []interface{}
+}
+
+// fakeStreamNode is an in-process JSON-RPC node for persistent connections. Single
+// requests are served concurrently, so their responses may be sent out of order.
+// eth_subscribe and eth_unsubscribe are built in.
+type fakeStreamNode struct {
+	t        *testing.T
+	handlers map[string]rpcHandler
+
+	mu      sync.Mutex
+	conns   map[fakeStreamConn]bool
+	dials   int
+	subs    map[string]fakeStreamSub
+	nextSub int
+	methods []string
+}
+
+func newFakeStreamNode(t *testing.T, handlers map[string]rpcHandler) *fakeStreamNode {
+	return &fakeStreamNode{
+		t:        t,
+		handlers: handlers,
+		conns:    make(map[fakeStreamConn]bool),
+		subs:     make(map[string]fakeStreamSub),
+	}
+}
+
+// serve handles the messages of a connection until read fails.
+func (n *fakeStreamNode) serve(conn fakeStreamConn, read func() ([]byte, error)) {
+	n.mu.Lock()
+	n.conns[conn] = true
+	n.dials++
+	n.mu.Unlock()
+	defer func() {
+		n.mu.Lock()
+		delete(n.conns, conn)
+		for id, sub := range n.subs {
+			if sub.conn == conn {
+				delete(n.subs, id)
+			}
+		}
+		n.mu.Unlock()
+		conn.close()
+	}()
+
+	for {
+		data, err := read()
+		if err != nil {
+			return
+		}
+		data = bytes.TrimSpace(data)
+		if len(data) > 0 && data[0] == '[' {
+			var reqs []JSONRPCRequest
+			require.NoError(n.t, json.Unmarshal(data, &reqs))
+			responses := make([]*JSONRPCResponse, len(reqs))
+			for i, req := range reqs {
+				responses[i] = n.handle(conn, req)
+			}
+			// Answer batches in reverse order, as nodes are allowed to.
+			for i, j := 0, len(responses)-1; i < j; i, j = i+1, j-1 {
+				responses[i], responses[j] = responses[j], responses[i]
+			}
+			out, _ := json.Marshal(responses)
+			conn.send(out)
+			continue
+		}
+
+		var req JSONRPCRequest
+		require.NoError(n.t, json.Unmarshal(data, &req))
+		go func() {
+			out, _ := json.Marshal(n.handle(conn, req))
+			conn.send(out)
+		}()
+	}
+}
+
+func (n *fakeStreamNode) handle(conn fakeStreamConn, req JSONRPCRequest) *JSONRPCResponse {
+	n.mu.Lock()
+	n.methods = append(n.methods, req.Method)
+	n.mu.Unlock()
+
+	switch req.Method {
+	case "eth_subscribe":
+		n.mu.Lock()
+		n.nextSub++
+		id := fmt.Sprintf("0x%x", n.nextSub)
+		n.subs[id] = fakeStreamSub{conn: conn, params: req.Params}
+		n.mu.Unlock()
+		return NewJSONRPCResponse(req.ID, id)
+	case "eth_unsubscribe":
+		n.mu.Lock()
+		_, ok := n.subs[req.Params[0].(string)]
+		delete(n.subs, req.Params[0].(string))
+		n.mu.Unlock()
+		return NewJSONRPCResponse(req.ID, ok)
+	}
+
+	handler, ok := n.handlers[req.Method]
+	if !ok {
+		return NewJSONRPCErrorResponse(req.ID, MethodNotFound, "method not found", nil)
+	}
+	result, rpcErr := handler(n.t, req)
+	if rpcErr != nil {
+		return NewJSONRPCErrorResponse(req.ID, rpcErr.Code, rpcErr.Message, rpcErr.Data)
+	}
+	return NewJSONRPCResponse(req.ID, result)
+}
+
+// notify sends result to every subscription of the given kind, such as "newHeads".
+func (n *fakeStreamNode) notify(kind string, result interface{}) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for id, sub := range n.subs {
+		if sub.params[0] != kind {
+			continue
+		}
+		out, _ := json.Marshal(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"method":  "eth_subscription",
+			"params":  map[string]interface{}{"subscription": id, "result": result},
+		})
+		sub.conn.send(out)
+	}
+}
+
+// subscriptions returns the params of the active subscriptions.
+func (n *fakeStreamNode) subscriptions() [][]interface{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var params [][]interface{}
+	for _, sub := range n.subs {
+		params = append(params, sub.params)
+	}
+	return params
+}
+
+func (n *fakeStreamNode) dialCount() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.dials
+}
+
+// dropConnections closes every connection.
+func (n *fakeStreamNode) dropConnections() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for conn := range n.conns {
+		conn.close()
+	}
+}
+
+// waitForSubscriptions waits until the node holds count subscriptions.
+func (n *fakeStreamNode) waitForSubscriptions(t *testing.T, count int) {
+	t.Helper()
+	assert.Eventually(t, func() bool { return len(n.subscriptions()) == count }, 5*time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrSubscriptionsNotSupported is returned when subscribing through a client that
+// connects over HTTP. Subscriptions need a WebSocket or IPC connection.
+var ErrSubscriptionsNotSupported = errors.New("subscriptions require a WebSocket or IPC connection")
+
+// Subscription delivers the notifications of an eth_subscribe subscription as typed
+// values. The subscription ends when the context passed to Subscribe is cancelled,
+// when Unsubscribe is called, or when it fails; C is then closed and Err reports
+// the failure, if any.
+//
+// If the connection drops, the client reconnects and subscribes again with the same
+// parameters. Notifications sent while disconnected are missed.
+type Subscription[T any] struct {
+	c       chan T
+	sub     *subscription
+	stream  *streamTransport
+	cancel  context.CancelFunc
+	stopped chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+// C returns the channel of notifications. It is closed when the subscription ends.
+func (s *Subscription[T]) C() <-chan T {
+	return s.c
+}
+
+// Err returns why the subscription ended, or nil if it is still active or was ended
+// by Unsubscribe or context cancellation.
+func (s *Subscription[T]) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Unsubscribe ends the subscription and waits until C is closed.
+func (s *Subscription[T]) Unsubscribe() {
+	s.cancel()
+	<-s.stopped
+}
+
+func (s *Subscription[T]) run(ctx context.Context) {
+	defer close(s.stopped)
+	defer close(s.c)
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.stream.unsubscribe(s.sub)
+			return
+		case <-s.sub.done:
+			s.stream.mu.Lock()
+			err := s.sub.err
+			s.stream.mu.Unlock()
+			s.fail(err)
+			return
+		case raw := <-s.sub.notifications:
+			var value T
+			if err := json.Unmarshal(raw, &value); err != nil {
+				s.fail(fmt.Errorf("failed to decode notification: %w", err))
+				s.stream.unsubscribe(s.sub)
+				return
+			}
+			select {
+			case s.c <- value:
+			case <-ctx.Done():
+				s.stream.unsubscribe(s.sub)
+				return
+			case <-s.sub.done:
+			}
+		}
+	}
+}
+
+func (s *Subscription[T]) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// Subscribe starts an eth_subscribe subscription with the given params and decodes
+// every notification into T. The subscription lasts until ctx is cancelled.
+// Returns ErrSubscriptionsNotSupported for HTTP clients.
+func Subscribe[T any](ctx context.Context, c *Client, params ...interface{}) (*Subscription[T], error) {
+	if c.stream == nil {
+		return nil, ErrSubscriptionsNotSupported
+	}
+	sub, err := c.stream.subscribe(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Subscription[T]{
+		c:       make(chan T),
+		sub:     sub,
+		stream:  c.stream,
+		cancel:  cancel,
+		stopped: make(chan struct{}),
+	}
+	go s.run(ctx)
+	return s, nil
+}
+
+// SubscribeNewHeads subscribes to new block headers. The blocks carry no transactions.
+func (c *Client) SubscribeNewHeads(ctx context.Context) (*Subscription[*Block], error) {
+	return Subscribe[*Block](ctx, c, "newHeads")
+}
+
+// SubscribePendingTransactions subscribes to the hashes of transactions entering the
+// node's transaction pool.
+func (c *Client) SubscribePendingTransactions(ctx context.Context) (*Subscription[common.Hash], error) {
+	return Subscribe[common.Hash](ctx, c, "newPendingTransactions")
+}
+
+// LogFilter selects logs by emitting contract and topics.
+type LogFilter struct {
+	// Addresses restricts logs to these contracts. Empty matches any contract.
+	Addresses []common.Address
+
+	// Topics restricts logs by topic position. Each position matches any of its
+	// hashes; a nil or empty position matches any topic.
+	Topics [][]common.Hash
+}
+
+// MarshalJSON encodes the filter in the eth_subscribe and eth_getLogs format.
+func (f LogFilter) MarshalJSON() ([]byte, error) {
+	filter := make(map[string]interface{})
+	if len(f.Addresses) > 0 {
+		filter["address"] = f.Addresses
+	}
+	if len(f.Topics) > 0 {
+		topics := make([]interface{}, len(f.Topics))
+		for i, position := range f.Topics {
+			if len(position) > 0 {
+				topics[i] = position
+			}
+		}
+		filter["topics"] = topics
+	}
+	return json.Marshal(filter)
+}
+
+// SubscribeLogs subscribes to logs matching filter. Logs of blocks removed by a
+// reorg are delivered again with Removed set.
+func (c *Client) SubscribeLogs(ctx context.Context, filter LogFilter) (*Subscription[*Log], error) {
+	return Subscribe[*Log](ctx, c, "logs", filter)
+}
@@ -0,0 +1,250 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/tempoxyz/tempo-go/pkg/signer"
+	"github.com/tempoxyz/tempo-go/pkg/transaction"
+)
+
+// Transaction is a transaction as returned by the eth_getTransactionBy* methods and
+// by blocks fetched with full transactions.
+//
+// Every transaction type is represented. Tempo (0x76) transactions are additionally
+// decoded into Tempo, which carries the calls, fee token, nonce key, validity window,
+// and signatures; the Ethereum-style To, Value, and Input fields are empty for them.
+type Transaction struct {
+	Hash common.Hash
+	Type uint8
+
+	// BlockHash, BlockNumber, and TxIndex are nil for pending transactions.
+	BlockHash   *common.Hash
+	BlockNumber *uint64
+	TxIndex     *uint64
+
+	From                 common.Address
+	Nonce                uint64
+	Gas                  uint64
+	GasPrice             *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	ChainID              *big.Int
+
+	// To, Value, and Input are set for legacy, EIP-2930, and EIP-1559 transactions.
+	To    *common.Address
+	Value *big.Int
+	Input []byte
+
+	// Tempo is the decoded transaction for Tempo (0x76) transactions, and nil otherwise.
+	Tempo *transaction.Tx
+
+	// FeePayer is the account sponsoring the fee, if the node reports one.
+	FeePayer *common.Address
+}
+
+// IsTempo returns true if this is a Tempo (0x76) transaction.
+func (tx *Transaction) IsTempo() bool {
+	return tx.Type == transaction.TxTypeTempo
+}
+
+type transactionJSON struct {
+	Hash                 common.Hash            `json:"hash"`
+	Type                 hexutil.Uint64         `json:"type"`
+	BlockHash            *common.Hash           `json:"blockHash"`
+	BlockNumber          *hexutil.Uint64        `json:"blockNumber"`
+	TxIndex              *hexutil.Uint64        `json:"transactionIndex"`
+	From                 common.Address         `json:"from"`
+	Nonce                hexutil.Uint64         `json:"nonce"`
+	Gas                  hexutil.Uint64         `json:"gas"`
+	GasPrice             *hexutil.Big           `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big           `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big           `json:"maxPriorityFeePerGas"`
+	ChainID              *hexutil.Big           `json:"chainId"`
+	To                   *common.Address        `json:"to"`
+	Value                *hexutil.Big           `json:"value"`
+	Input                hexutil.Bytes          `json:"input"`
+	AccessList           transaction.AccessList `json:"accessList"`
+
+	// Tempo transaction fields
+	Calls             []callJSON      `json:"calls"`
+	NonceKey          *hexutil.Big    `json:"nonceKey"`
+	ValidBefore       *hexutil.Uint64 `json:"validBefore"`
+	ValidAfter        *hexutil.Uint64 `json:"validAfter"`
+	FeeToken          *common.Address `json:"feeToken"`
+	FeePayer          *common.Address `json:"feePayer"`
+	FeePayerSignature *signatureJSON  `json:"feePayerSignature"`
+	Signature         *signatureJSON  `json:"signature"`
+
+	// Flat signature fields used by non-Tempo transactions
+	signatureJSON
+}
+
+type callJSON struct {
+	To    *common.Address `json:"to"`
+	Value *hexutil.Big    `json:"value"`
+	Input hexutil.Bytes   `json:"input"`
+	Data  hexutil.Bytes   `json:"data"`
+}
+
+type signatureJSON struct {
+	Type    string          `json:"type,omitempty"`
+	R       *hexutil.Big    `json:"r"`
+	S       *hexutil.Big    `json:"s"`
+	YParity *hexutil.Uint64 `json:"yParity"`
+	V       *hexutil.Big    `json:"v"`
+}
+
+// signature converts the JSON fields into a signature, or returns nil if R or S is missing.
+func (s *signatureJSON) signature() *signer.Signature {
+	if s == nil || s.R == nil || s.S == nil {
+		return nil
+	}
+
+	var yParity uint8
+	switch {
+	case s.YParity != nil:
+		yParity = uint8(*s.YParity)
+	case s.V != nil:
+		v := (*big.Int)(s.V).Uint64()
+		if v >= 27 {
+			v -= 27
+		}
+		yParity = uint8(v & 1)
+	}
+
+	return signer.NewSignature((*big.Int)(s.R), (*big.Int)(s.S), yParity)
+}
+
+// UnmarshalJSON decodes a transaction from its JSON-RPC representation.
+func (tx *Transaction) UnmarshalJSON(data []byte) error {
+	var dec transactionJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+
+	*tx = Transaction{
+		Hash:                 dec.Hash,
+		Type:                 uint8(dec.Type),
+		BlockHash:            dec.BlockHash,
+		BlockNumber:          (*uint64)(dec.BlockNumber),
+		TxIndex:              (*uint64)(dec.TxIndex),
+		From:                 dec.From,
+		Nonce:                uint64(dec.Nonce),
+		Gas:                  uint64(dec.Gas),
+		GasPrice:             (*big.Int)(dec.GasPrice),
+		MaxFeePerGas:         (*big.Int)(dec.MaxFeePerGas),
+		MaxPriorityFeePerGas: (*big.Int)(dec.MaxPriorityFeePerGas),
+		ChainID:              (*big.Int)(dec.ChainID),
+		FeePayer:             dec.FeePayer,
+	}
+
+	if !tx.IsTempo() {
+		tx.To = dec.To
+		tx.Value = (*big.Int)(dec.Value)
+		tx.Input = dec.Input
+		return nil
+	}
+
+	tx.Tempo = dec.tempoTx()
+	return nil
+}
+
+// tempoTx builds the decoded Tempo transaction from its JSON fields.
+func (dec *transactionJSON) tempoTx() *transaction.Tx {
+	tx := transaction.New()
+	if dec.ChainID != nil {
+		tx.ChainID = (*big.Int)(dec.ChainID)
+	}
+	if dec.MaxPriorityFeePerGas != nil {
+		tx.MaxPriorityFeePerGas = (*big.Int)(dec.MaxPriorityFeePerGas)
+	}
+	if dec.MaxFeePerGas != nil {
+		tx.MaxFeePerGas = (*big.Int)(dec.MaxFeePerGas)
+	}
+	if dec.NonceKey != nil {
+		tx.NonceKey = (*big.Int)(dec.NonceKey)
+	}
+	if dec.ValidBefore != nil {
+		tx.ValidBefore = uint64(*dec.ValidBefore)
+	}
+	if dec.ValidAfter != nil {
+		tx.ValidAfter = uint64(*dec.ValidAfter)
+	}
+	if dec.FeeToken != nil {
+		tx.FeeToken = *dec.FeeToken
+	}
+	tx.Gas = uint64(dec.Gas)
+	tx.Nonce = uint64(dec.Nonce)
+	tx.AccessList = dec.AccessList
+	tx.From = dec.From
+
+	tx.Calls = make([]transaction.Call, 0, len(dec.Calls))
+	for _, c := range dec.Calls {
+		call := transaction.Call{
+			To:    c.To,
+			Value: big.NewInt(0),
+			Data:  c.Input,
+		}
+		if c.Value != nil {
+			call.Value = (*big.Int)(c.Value)
+		}
+		if call.Data == nil {
+			call.Data = c.Data
+		}
+		if call.Data == nil {
+			call.Data = []byte{}
+		}
+		tx.Calls = append(tx.Calls, call)
+	}
+
+	envelope := dec.Signature
+	if envelope == nil {
+		envelope = &dec.signatureJSON
+	}
+	if sig := envelope.signature(); sig != nil {
+		sigType := envelope.Type
+		if sigType == "" {
+			sigType = transaction.SignatureTypeSecp256k1
+		}
+		tx.Signature = &signer.SignatureEnvelope{Type: sigType, Signature: sig}
+	}
+	tx.FeePayerSignature = dec.FeePayerSignature.signature()
+
+	return tx
+}
+
+// GetTransactionByHash gets a transaction by hash, including pending transactions.
+// Returns ErrNotFound if the node does not know the transaction.
+func (c *Client) GetTransactionByHash(ctx context.Context, hash common.Hash) (*Transaction, error) {
+	var tx *Transaction
+	if err := c.call(ctx, &tx, "eth_getTransactionByHash", hash); err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, fmt.Errorf("%w: transaction %s", ErrNotFound, hash.Hex())
+	}
+	return tx, nil
+}
+
+// GetTransactionByBlockAndIndex gets the transaction at the given index in a block.
+// Returns ErrNotFound if the block or index does not exist.
+func (c *Client) GetTransactionByBlockAndIndex(ctx context.Context, block BlockSelector, index uint64) (*Transaction, error) {
+	method, id := "eth_getTransactionByBlockNumberAndIndex", block.String()
+	if block.hash != nil {
+		method = "eth_getTransactionByBlockHashAndIndex"
+	}
+
+	var tx *Transaction
+	if err := c.call(ctx, &tx, method, id, hexutil.EncodeUint64(index)); err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, fmt.Errorf("%w: transaction %d in block %s", ErrNotFound, index, id)
+	}
+	return tx, nil
+}
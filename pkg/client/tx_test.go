@@ -0,0 +1,170 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempoxyz/tempo-go/pkg/signer"
+	"github.com/tempoxyz/tempo-go/pkg/transaction"
+)
+
+// tempoTxJSON renders a signed Tempo transaction the way a node returns it.
+func tempoTxJSON(t *testing.T, tx *transaction.Tx) map[string]interface{} {
+	t.Helper()
+	hash, err := tx.Hash()
+	require.NoError(t, err)
+
+	calls := make([]interface{}, 0, len(tx.Calls))
+	for _, call := range tx.Calls {
+		calls = append(calls, map[string]interface{}{
+			"to":    call.To.Hex(),
+			"value": hexutil.EncodeBig(call.Value),
+			"input": hexutil.Encode(call.Data),
+		})
+	}
+
+	return map[string]interface{}{
+		"hash":                 hash.Hex(),
+		"type":                 "0x76",
+		"blockHash":            "0x2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0",
+		"blockNumber":          "0x10",
+		"transactionIndex":     "0x0",
+		"from":                 tx.From.Hex(),
+		"chainId":              hexutil.EncodeBig(tx.ChainID),
+		"nonce":                hexutil.EncodeUint64(tx.Nonce),
+		"gas":                  hexutil.EncodeUint64(tx.Gas),
+		"maxFeePerGas":         hexutil.EncodeBig(tx.MaxFeePerGas),
+		"maxPriorityFeePerGas": hexutil.EncodeBig(tx.MaxPriorityFeePerGas),
+		"accessList":           []interface{}{},
+		"calls":                calls,
+		"nonceKey":             hexutil.EncodeBig(tx.NonceKey),
+		"validBefore":          hexutil.EncodeUint64(tx.ValidBefore),
+		"validAfter":           hexutil.EncodeUint64(tx.ValidAfter),
+		"feeToken":             tx.FeeToken.Hex(),
+		"signature": map[string]interface{}{
+			"type":    tx.Signature.Type,
+			"r":       hexutil.EncodeBig(tx.Signature.Signature.R),
+			"s":       hexutil.EncodeBig(tx.Signature.Signature.S),
+			"yParity": hexutil.EncodeUint64(uint64(tx.Signature.Signature.YParity)),
+		},
+	}
+}
+
+func dynamicFeeTxJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"hash":                 testTxHash,
+		"type":                 "0x2",
+		"blockHash":            nil,
+		"blockNumber":          nil,
+		"transactionIndex":     nil,
+		"from":                 "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266",
+		"to":                   "0x70997970c51812dc3a010c7d01b50e0d17dc79c8",
+		"value":                "0xde0b6b3a7640000",
+		"input":                "0x",
+		"chainId":              "0xa5bd",
+		"nonce":                "0x7",
+		"gas":                  "0x5208",
+		"maxFeePerGas":         "0x2540be400",
+		"maxPriorityFeePerGas": "0x3b9aca00",
+		"r":                    "0x1",
+		"s":                    "0x2",
+		"yParity":              "0x1",
+	}
+}
+
+func TestGetTransactionByHash(t *testing.T) {
+	t.Run("tempo transaction", func(t *testing.T) {
+		signed := newSignedTx(t, transaction.ChainIDTempoTestnet)
+		signed.NonceKey = big.NewInt(5)
+		signed.ValidBefore = 2000000000
+		signed.FeeToken = transaction.AlphaUSDAddress
+		sgn, err := signer.NewSigner(testPrivateKey)
+		require.NoError(t, err)
+		require.NoError(t, transaction.SignTransaction(signed, sgn))
+		hash, err := signed.Hash()
+		require.NoError(t, err)
+
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_getTransactionByHash": func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError) {
+				assert.Equal(t, hash.Hex(), req.Params[0])
+				return tempoTxJSON(t, signed), nil
+			},
+		})
+
+		tx, err := New(server.URL).GetTransactionByHash(context.Background(), hash)
+		require.NoError(t, err)
+
+		assert.True(t, tx.IsTempo())
+		assert.Equal(t, uint64(16), *tx.BlockNumber)
+		assert.Nil(t, tx.To)
+		require.NotNil(t, tx.Tempo)
+		assert.Equal(t, 0, tx.Tempo.NonceKey.Cmp(big.NewInt(5)))
+		assert.Equal(t, uint64(2000000000), tx.Tempo.ValidBefore)
+		assert.Equal(t, transaction.AlphaUSDAddress, tx.Tempo.FeeToken)
+		require.Len(t, tx.Tempo.Calls, 1)
+		assert.Equal(t, *signed.Calls[0].To, *tx.Tempo.Calls[0].To)
+
+		// The decoded transaction hashes and verifies exactly like the original.
+		decodedHash, err := tx.Tempo.Hash()
+		require.NoError(t, err)
+		assert.Equal(t, hash, decodedHash)
+		sender, err := transaction.VerifySignature(tx.Tempo)
+		require.NoError(t, err)
+		assert.Equal(t, sgn.Address(), sender)
+	})
+
+	t.Run("dynamic fee transaction", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_getTransactionByHash": result(dynamicFeeTxJSON()),
+		})
+
+		tx, err := New(server.URL).GetTransactionByHash(context.Background(), common.HexToHash(testTxHash))
+		require.NoError(t, err)
+
+		assert.False(t, tx.IsTempo())
+		assert.Nil(t, tx.Tempo)
+		assert.Nil(t, tx.BlockNumber)
+		assert.Equal(t, uint64(7), tx.Nonce)
+		assert.Equal(t, common.HexToAddress("0x70997970c51812dc3a010c7d01b50e0d17dc79c8"), *tx.To)
+		assert.Equal(t, big.NewInt(1e18), tx.Value)
+		assert.Empty(t, tx.Input)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		server := newRPCServer(t, map[string]rpcHandler{
+			"eth_getTransactionByHash": result(nil),
+		})
+
+		_, err := New(server.URL).GetTransactionByHash(context.Background(), common.HexToHash(testTxHash))
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+}
+
+func TestGetTransactionByBlockAndIndex(t *testing.T) {
+	blockHash := common.HexToHash("0x2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0b2a5e3d5ba1f0")
+
+	server := newRPCServer(t, map[string]rpcHandler{
+		"eth_getTransactionByBlockNumberAndIndex": func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError) {
+			assert.Equal(t, []interface{}{"finalized", "0x2"}, req.Params)
+			return dynamicFeeTxJSON(), nil
+		},
+		"eth_getTransactionByBlockHashAndIndex": func(t *testing.T, req JSONRPCRequest) (interface{}, *JSONRPCError) {
+			assert.Equal(t, []interface{}{blockHash.Hex(), "0x0"}, req.Params)
+			return nil, nil
+		},
+	})
+	client := New(server.URL)
+
+	tx, err := client.GetTransactionByBlockAndIndex(context.Background(), Finalized, 2)
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToHash(testTxHash), tx.Hash)
+
+	_, err = client.GetTransactionByBlockAndIndex(context.Background(), AtBlockHash(blockHash), 0)
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
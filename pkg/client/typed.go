@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// Call sends a JSON-RPC request and decodes its result into T. JSON-RPC errors
+// are returned wrapped with the method name, and a null result returns ErrNotFound.
+// To simulate a transaction, use Client.Call instead.
+//
+//	number, err := client.Call[client.HexUint64](ctx, c, "eth_blockNumber")
+//	block, err := client.Call[*client.Block](ctx, c, "eth_getBlockByHash", hash, false)
+func Call[T any](ctx context.Context, c *Client, method string, params ...interface{}) (T, error) {
+	var result T
+	response, err := c.SendRequest(ctx, method, params...)
+	if err != nil {
+		return result, err
+	}
+	if err := bindResult(response, &result); err != nil {
+		return result, fmt.Errorf("%s: %w", method, err)
+	}
+	return result, nil
+}
+
+// bindResult decodes the result of a response into result, or returns its JSON-RPC
+// error or ErrNotFound for a null result.
+func bindResult(response *JSONRPCResponse, result interface{}) error {
+	if err := response.CheckError(); err != nil {
+		return err
+	}
+	if response.isNull() {
+		return ErrNotFound
+	}
+	if result == nil {
+		return nil
+	}
+	return response.DecodeResult(result)
+}
+
+// TypedBatch is a batch of requests whose results are decoded into their own
+// destinations. Use NewTypedBatch to create one and SendTypedBatch to send it.
+//
+//	var number client.HexUint64
+//	var balance client.HexBig
+//	batch := client.NewTypedBatch().
+//		Add(&number, "eth_blockNumber").
+//		Add(&balance, "eth_getBalance", address, client.Latest)
+//	if err := c.SendTypedBatch(ctx, batch); err != nil {
+//		return err // the batch could not be sent
+//	}
+//	if err := batch.Err(1); err != nil {
+//		return err // the balance request failed, see BatchItemError
+//	}
+type TypedBatch struct {
+	batch   *BatchRequest
+	results []interface{}
+	errs    []*BatchItemError
+}
+
+// NewTypedBatch creates a new typed batch.
+func NewTypedBatch() *TypedBatch {
+	return &TypedBatch{batch: NewBatchRequest()}
+}
+
+// Add adds a request whose result is decoded into result, a pointer. A nil result
+// discards the result.
+func (b *TypedBatch) Add(result interface{}, method string, params ...interface{}) *TypedBatch {
+	b.batch.Add(method, params...)
+	b.results = append(b.results, result)
+	return b
+}
+
+// Len returns the number of requests in the batch.
+func (b *TypedBatch) Len() int {
+	return b.batch.Len()
+}
+
+// Err returns the error of the i-th request after the batch was sent, or nil if it
+// succeeded. The error is a *BatchItemError.
+func (b *TypedBatch) Err(i int) error {
+	if i < 0 || i >= len(b.errs) || b.errs[i] == nil {
+		return nil
+	}
+	return b.errs[i]
+}
+
+// Errors returns the errors of the requests that failed, in request order.
+func (b *TypedBatch) Errors() []*BatchItemError {
+	var errs []*BatchItemError
+	for _, err := range b.errs {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// SendTypedBatch sends a typed batch and decodes every result into its destination.
+// The batch is split into chunks like SendBatch. The returned error is only set when
+// a chunk could not be sent; the requests of that chunk fail with its error, and the
+// results of the other chunks are still decoded. The error of each request is
+// reported by Err and Errors.
+func (c *Client) SendTypedBatch(ctx context.Context, b *TypedBatch) error {
+	b.errs = make([]*BatchItemError, b.Len())
+	if b.Len() == 0 {
+		return nil
+	}
+	chunks, err := c.chunkBatch(b.batch.Requests())
+	if err != nil {
+		return err
+	}
+	c.sendChunks(ctx, chunks)
+
+	var chunkErr error
+	for _, chunk := range chunks {
+		for j, request := range chunk.requests {
+			i := chunk.offset + j
+			err := chunk.err
+			if err == nil {
+				err = bindResult(chunk.responses[j], b.results[i])
+			}
+			if err != nil {
+				b.errs[i] = &BatchItemError{Index: i, Method: request.Method, Err: err}
+			}
+		}
+		if chunk.err != nil && chunkErr == nil {
+			chunkErr = chunk.err
+		}
+	}
+	return chunkErr
+}
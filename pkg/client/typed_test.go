@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHexTypes(t *testing.T) {
+	t.Run("HexUint64", func(t *testing.T) {
+		var h HexUint64
+		require.NoError(t, json.Unmarshal([]byte(`"0x2a"`), &h))
+		assert.Equal(t, HexUint64(42), h)
+		assert.Equal(t, "0x2a", h.String())
+
+		out, err := json.Marshal(h)
+		require.NoError(t, err)
+		assert.Equal(t, `"0x2a"`, string(out))
+
+		assert.Error(t, json.Unmarshal([]byte(`42`), &h))
+	})
+
+	t.Run("HexBig", func(t *testing.T) {
+		var h HexBig
+		require.NoError(t, json.Unmarshal([]byte(`"0xde0b6b3a7640000000"`), &h))
+		want, _ := new(big.Int).SetString("de0b6b3a7640000000", 16)
+		assert.Equal(t, 0, want.Cmp(h.Big()))
+		assert.Equal(t, "0xde0b6b3a7640000000", h.String())
+
+		out, err := json.Marshal(&h)
+		require.NoError(t, err)
+		assert.Equal(t, `"0xde0b6b3a7640000000"`, string(out))
+	})
+
+	t.Run("HexBytes", func(t *testing.T) {
+		var h HexBytes
+		require.NoError(t, json.Unmarshal([]byte(`"0x0102ff"`), &h))
+		assert.Equal(t, HexBytes{0x01, 0x02, 0xff}, h)
+		assert.Equal(t, "0x0102ff", h.String())
+
+		out, err := json.Marshal(h)
+		require.NoError(t, err)
+		assert.Equal(t, `"0x0102ff"`, string(out))
+	})
+}
+
+func TestDecodeResult(t *testing.T) {
+	t.Run("large numbers keep their precision", func(t *testing.T) {
+		var response JSONRPCResponse
+		require.NoError(t, json.Unmarshal([]byte(`{"jsonrpc":"2.0","id":1,"result":12345678901234567890}`), &response))
+		assert.Equal(t, "12345678901234567890", string(response.RawResult))
+		assert.Equal(t, float64(12345678901234567890), response.Result)
+
+		var n uint64
+		require.NoError(t, response.DecodeResult(&n))
+		assert.Equal(t, uint64(12345678901234567890), n)
+	})
+
+	t.Run("null result", func(t *testing.T) {
+		var response JSONRPCResponse
+		require.NoError(t, json.Unmarshal([]byte(`{"jsonrpc":"2.0","id":1,"result":null}`), &response))
+		assert.Nil(t, response.RawResult)
+		assert.Nil(t, response.Result)
+
+		n := uint64(7)
+		require.NoError(t, response.DecodeResult(&n))
+		assert.Equal(t, uint64(7), n)
+	})
+
+	t.Run("built responses", func(t *testing.T) {
+		var h HexUint64
+		require.NoError(t, NewJSONRPCResponse(1, "0x10").DecodeResult(&h))
+		assert.Equal(t, HexUint64(16), h)
+	})
+
+	t.Run("replaced result", func(t *testing.T) {
+		var response JSONRPCResponse
+		require.NoError(t, json.Unmarshal([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`), &response))
+		response.SetResult("0x20")
+		assert.Equal(t, "0x20", response.Result)
+
+		var h HexUint64
+		require.NoError(t, response.DecodeResult(&h))
+		assert.Equal(t, HexUint64(32), h)
+	})
+}
+
+func TestTypedCall(t *testing.T) {
+	ctx := context.Background()
+	hash := common.HexToHash("0xabc")
+	server := newRPCServer(t, map[string]rpcHandler{
+		"eth_blockNumber": result("0x10"),
+		"eth_getBalance":  result("0xde0b6b3a7640000000"),
+		"eth_getTransactionReceipt": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+			return nil, nil
+		},
+		"eth_getBlockByHash": result(map[string]interface{}{"hash": hash, "number": "0x5"}),
+		"eth_call": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+			return nil, &JSONRPCError{Code: InternalError, Message: "boom"}
+		},
+	})
+	c := New(server.URL)
+
+	number, err := Call[HexUint64](ctx, c, "eth_blockNumber")
+	require.NoError(t, err)
+	assert.Equal(t, HexUint64(16), number)
+
+	balance, err := Call[*HexBig](ctx, c, "eth_getBalance", common.Address{}, Latest)
+	require.NoError(t, err)
+	assert.Equal(t, "0xde0b6b3a7640000000", balance.String())
+
+	block, err := Call[*Block](ctx, c, "eth_getBlockByHash", hash, false)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), block.Number)
+
+	_, err = Call[*Receipt](ctx, c, "eth_getTransactionReceipt", hash)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = Call[HexBytes](ctx, c, "eth_call")
+	var rpcErr *JSONRPCError
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Contains(t, err.Error(), "eth_call")
+
+	_, err = Call[HexUint64](ctx, c, "eth_getBlockByHash", hash, false)
+	assert.ErrorContains(t, err, "failed to decode result")
+}
+
+func TestSendTypedBatch(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []JSONRPCRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqs))
+		results := map[string]interface{}{"eth_blockNumber": "0x10", "eth_chainId": "0xa5bf", "eth_getTransactionReceipt": nil}
+		responses := make([]*JSONRPCResponse, len(reqs))
+		for i, req := range reqs {
+			result, ok := results[req.Method]
+			responses[i] = NewJSONRPCResponse(req.ID, result)
+			if !ok {
+				responses[i] = NewJSONRPCErrorResponse(req.ID, MethodNotFound, "method not found", nil)
+			}
+		}
+		json.NewEncoder(w).Encode(responses)
+	}))
+	defer server.Close()
+	c := New(server.URL)
+
+	var number HexUint64
+	var chainID HexBig
+	var receipt *Receipt
+	batch := NewTypedBatch().
+		Add(&number, "eth_blockNumber").
+		Add(&chainID, "eth_chainId").
+		Add(&receipt, "eth_getTransactionReceipt", common.Hash{}).
+		Add(nil, "eth_unknown")
+	require.Equal(t, 4, batch.Len())
+
+	require.NoError(t, c.SendTypedBatch(ctx, batch))
+	assert.NoError(t, batch.Err(0))
+	assert.Equal(t, HexUint64(16), number)
+	assert.NoError(t, batch.Err(1))
+	assert.Equal(t, int64(42431), chainID.Big().Int64())
+	assert.ErrorIs(t, batch.Err(2), ErrNotFound)
+	assert.Nil(t, receipt)
+	var rpcErr *JSONRPCError
+	require.ErrorAs(t, batch.Err(3), &rpcErr)
+	assert.Equal(t, MethodNotFound, rpcErr.Code)
+	assert.NoError(t, batch.Err(4))
+}
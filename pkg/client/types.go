@@ -1,6 +1,9 @@
 package client
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 // JSONRPCRequest represents a JSON-RPC 2.0 request.
 type JSONRPCRequest struct {
@@ -16,6 +19,58 @@ type JSONRPCResponse struct {
 	ID      interface{}   `json:"id"`
 	Result  interface{}   `json:"result,omitempty"`
 	Error   *JSONRPCError `json:"error,omitempty"`
+
+	// RawResult holds the result exactly as received from the node, or nil for a null
+	// result and for responses built with NewJSONRPCResponse. DecodeResult and the
+	// typed calls decode it directly, so large numbers keep their precision.
+	// Interceptors that replace the result should use SetResult, which clears it.
+	RawResult json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a response, keeping the raw result alongside Result.
+func (r *JSONRPCResponse) UnmarshalJSON(data []byte) error {
+	var dec struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      interface{}     `json:"id"`
+		Result  json.RawMessage `json:"result"`
+		Error   *JSONRPCError   `json:"error"`
+	}
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+
+	*r = JSONRPCResponse{JSONRPC: dec.JSONRPC, ID: dec.ID, Error: dec.Error}
+	if len(dec.Result) > 0 && string(dec.Result) != "null" {
+		if err := json.Unmarshal(dec.Result, &r.Result); err != nil {
+			return err
+		}
+		r.RawResult = dec.Result
+	}
+	return nil
+}
+
+// SetResult replaces the result of the response with v.
+func (r *JSONRPCResponse) SetResult(v interface{}) {
+	r.Result = v
+	r.RawResult = nil
+}
+
+// DecodeResult decodes the result of the response into v, from RawResult if the
+// response was received from a node and from Result otherwise.
+// A null result leaves v untouched.
+func (r *JSONRPCResponse) DecodeResult(v interface{}) error {
+	if r.RawResult != nil {
+		if err := json.Unmarshal(r.RawResult, v); err != nil {
+			return fmt.Errorf("failed to decode result: %w", err)
+		}
+		return nil
+	}
+	return decodeResult(r.Result, v)
+}
+
+// isNull reports whether the response has a null or missing result.
+func (r *JSONRPCResponse) isNull() bool {
+	return r.RawResult == nil && r.Result == nil
 }
 
 // JSONRPCError represents a JSON-RPC 2.0 error.
@@ -30,6 +85,12 @@ func (e *JSONRPCError) Error() string {
 	return fmt.Sprintf("RPC error %d: %s", e.Code, e.Message)
 }
 
+// Is reports whether the error matches target, one of the node rejection sentinels
+// such as ErrNonceTooLow. The match is based on the error code and message.
+func (e *JSONRPCError) Is(target error) bool {
+	return target != nil && classifyRPCError(e.Code, e.Message) == target
+}
+
 // CheckError returns an error if the response contains an RPC error.
 func (r *JSONRPCResponse) CheckError() error {
 	if r.Error != nil {
@@ -115,3 +176,55 @@ func (b *BatchRequest) Requests() []*JSONRPCRequest {
 func (b *BatchRequest) Len() int {
 	return len(b.requests)
 }
+
+// matchResponses matches responses to their requests by ID and returns them in request
+// order, with the IDs of the requests. Nodes may answer batch items in any order, and
+// proxies may reorder them further.
+func matchResponses(requests []*JSONRPCRequest, responses []*JSONRPCResponse) ([]*JSONRPCResponse, error) {
+	if len(requests) == 1 && len(responses) == 1 && responses[0] != nil && responses[0].ID == nil && responses[0].Error != nil {
+		// Nodes answer requests they cannot parse with a null ID.
+		responses[0].ID = requests[0].ID
+		return responses, nil
+	}
+
+	index := make(map[string]int, len(requests))
+	for i, request := range requests {
+		key := idKey(request.ID)
+		if _, ok := index[key]; ok {
+			return nil, fmt.Errorf("duplicate request ID %v (%s)", request.ID, request.Method)
+		}
+		index[key] = i
+	}
+
+	ordered := make([]*JSONRPCResponse, len(requests))
+	for _, response := range responses {
+		if response == nil {
+			continue
+		}
+		i, ok := index[idKey(response.ID)]
+		if !ok {
+			return nil, &ResponseIDError{Err: ErrUnexpectedResponse, ID: response.ID}
+		}
+		if ordered[i] != nil {
+			return nil, &ResponseIDError{Err: ErrDuplicateResponse, ID: requests[i].ID, Method: requests[i].Method}
+		}
+		response.ID = requests[i].ID
+		ordered[i] = response
+	}
+	for i, response := range ordered {
+		if response == nil {
+			return nil, &ResponseIDError{Err: ErrMissingResponse, ID: requests[i].ID, Method: requests[i].Method}
+		}
+	}
+	return ordered, nil
+}
+
+// idKey returns the JSON encoding of a request or response ID, so that an ID sent as
+// an integer matches the float64 it is decoded into.
+func idKey(id interface{}) string {
+	data, err := json.Marshal(id)
+	if err != nil {
+		return fmt.Sprint(id)
+	}
+	return string(data)
+}
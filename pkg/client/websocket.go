@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// wsHandshakeTimeout bounds the WebSocket opening handshake.
+const wsHandshakeTimeout = 30 * time.Second
+
+// isWebSocketURL reports whether rawURL uses the ws or wss scheme.
+func isWebSocketURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	return scheme == "ws" || scheme == "wss"
+}
+
+// newWebSocketTransport creates a transport that dials rawURL over WebSocket, sending
+// the headers returned by handshakeHeader, including auth, with every handshake.
+// Messages larger than maxMessageSize bytes close the connection.
+func newWebSocketTransport(rawURL string, maxMessageSize int64, handshakeHeader func(ctx context.Context) (http.Header, error)) *streamTransport {
+	return newStreamTransport(func(ctx context.Context) (streamCodec, error) {
+		header, err := handshakeHeader(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		dialer := websocket.Dialer{
+			Proxy:            http.ProxyFromEnvironment,
+			HandshakeTimeout: wsHandshakeTimeout,
+		}
+		conn, resp, err := dialer.DialContext(ctx, rawURL, header)
+		if err != nil {
+			if resp != nil && resp.StatusCode != http.StatusSwitchingProtocols {
+				return nil, newHTTPError(resp, readErrorBody(resp))
+			}
+			return nil, redactError(err, rawURL)
+		}
+		conn.SetReadLimit(maxMessageSize)
+		return &wsCodec{conn: conn}, nil
+	})
+}
+
+// wsCodec sends every JSON-RPC message as a WebSocket text message.
+type wsCodec struct {
+	conn *websocket.Conn
+}
+
+func (c *wsCodec) writeMessage(data []byte) error {
+	return c.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+func (c *wsCodec) readMessage() ([]byte, error) {
+	_, data, err := c.conn.ReadMessage()
+	return data, err
+}
+
+func (c *wsCodec) close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,314 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wsServerConn adapts a server-side WebSocket to fakeStreamConn.
+type wsServerConn struct {
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+func (c *wsServerConn) send(data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+func (c *wsServerConn) close() {
+	c.conn.Close()
+}
+
+// newWSNode starts node behind a WebSocket server and returns its ws:// URL.
+func newWSNode(t *testing.T, node *fakeStreamNode) string {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); ok && (user != "user" || pass != "pass") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		node.serve(&wsServerConn{conn: conn}, func() ([]byte, error) {
+			_, data, err := conn.ReadMessage()
+			return data, err
+		})
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func newWSClient(t *testing.T, node *fakeStreamNode, opts ...Option) *Client {
+	t.Helper()
+	c := New(newWSNode(t, node), opts...)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestWebSocketCalls(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("multiplexed requests", func(t *testing.T) {
+		release := make(chan struct{})
+		node := newFakeStreamNode(t, map[string]rpcHandler{
+			"eth_blockNumber": result("0x10"),
+			"slow_method": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+				<-release
+				return "slow", nil
+			},
+		})
+		c := newWSClient(t, node)
+
+		slow := make(chan *JSONRPCResponse)
+		go func() {
+			response, err := c.SendRequest(ctx, "slow_method")
+			assert.NoError(t, err)
+			slow <- response
+		}()
+
+		// The fast request completes while the slow one is still pending.
+		number, err := c.GetBlockNumber(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(16), number)
+
+		close(release)
+		response := <-slow
+		assert.Equal(t, "slow", response.Result)
+		assert.Equal(t, 1, node.dialCount())
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		node := newFakeStreamNode(t, map[string]rpcHandler{
+			"eth_blockNumber": result("0x10"),
+			"eth_chainId":     result("0xa5bf"),
+		})
+		c := newWSClient(t, node)
+
+		batch := NewBatchRequest().Add("eth_blockNumber").Add("eth_chainId")
+		responses, err := c.SendBatch(ctx, batch)
+		require.NoError(t, err)
+		require.Len(t, responses, 2)
+		assert.Equal(t, "0x10", responses[0].Result)
+		assert.Equal(t, 1, responses[0].ID)
+		assert.Equal(t, "0xa5bf", responses[1].Result)
+		assert.Equal(t, 2, responses[1].ID)
+	})
+
+	t.Run("basic auth", func(t *testing.T) {
+		node := newFakeStreamNode(t, map[string]rpcHandler{"eth_blockNumber": result("0x1")})
+		url := newWSNode(t, node)
+
+		_, err := New(url, WithAuth("user", "pass")).GetBlockNumber(ctx)
+		assert.NoError(t, err)
+
+		_, err = New(url, WithAuth("user", "wrong")).GetBlockNumber(ctx)
+		var httpErr *HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusUnauthorized, httpErr.StatusCode)
+	})
+
+	t.Run("call headers are refused", func(t *testing.T) {
+		node := newFakeStreamNode(t, map[string]rpcHandler{"eth_blockNumber": result("0x1")})
+		setHeader := InterceptorFunc(func(ctx context.Context, call *Invocation, next Invoker) ([]*JSONRPCResponse, error) {
+			call.SetHeader("X-Request-Id", "1")
+			return next(ctx, call)
+		})
+		c := newWSClient(t, node, WithInterceptors(setHeader))
+
+		_, err := c.GetBlockNumber(ctx)
+		assert.ErrorIs(t, err, errCallHeaders)
+	})
+
+	t.Run("reconnects for later requests", func(t *testing.T) {
+		node := newFakeStreamNode(t, map[string]rpcHandler{"eth_blockNumber": result("0x1")})
+		c := newWSClient(t, node)
+
+		_, err := c.GetBlockNumber(ctx)
+		require.NoError(t, err)
+		node.dropConnections()
+
+		assert.Eventually(t, func() bool {
+			_, err := c.GetBlockNumber(ctx)
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, 2, node.dialCount())
+	})
+
+	t.Run("in-flight request fails when the connection drops", func(t *testing.T) {
+		var drops atomic.Int32
+		var node *fakeStreamNode
+		node = newFakeStreamNode(t, map[string]rpcHandler{
+			"eth_blockNumber": func(*testing.T, JSONRPCRequest) (interface{}, *JSONRPCError) {
+				if drops.Add(1) == 1 {
+					node.dropConnections()
+					time.Sleep(10 * time.Millisecond)
+				}
+				return "0x1", nil
+			},
+		})
+
+		_, err := newWSClient(t, node).GetBlockNumber(ctx)
+		assert.ErrorIs(t, err, errConnectionLost)
+
+		// With a retry policy, the request is sent again on a new connection.
+		drops.Store(0)
+		number, err := newWSClient(t, node, WithRetry(fastRetry())).GetBlockNumber(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), number)
+	})
+}
+
+func TestWebSocketSubscriptions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("new heads", func(t *testing.T) {
+		node := newFakeStreamNode(t, nil)
+		c := newWSClient(t, node)
+
+		sub, err := c.SubscribeNewHeads(ctx)
+		require.NoError(t, err)
+		defer sub.Unsubscribe()
+
+		node.notify("newHeads", map[string]interface{}{"number": "0x10", "hash": common.HexToHash("0xaa")})
+		node.notify("newHeads", map[string]interface{}{"number": "0x11", "hash": common.HexToHash("0xbb")})
+
+		for _, want := range []uint64{16, 17} {
+			select {
+			case head := <-sub.C():
+				assert.Equal(t, want, head.Number)
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for head")
+			}
+		}
+	})
+
+	t.Run("logs", func(t *testing.T) {
+		node := newFakeStreamNode(t, nil)
+		c := newWSClient(t, node)
+
+		token := common.HexToAddress("0x20c0000000000000000000000000000000000001")
+		topic := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+		sub, err := c.SubscribeLogs(ctx, LogFilter{
+			Addresses: []common.Address{token},
+			Topics:    [][]common.Hash{{topic}, nil},
+		})
+		require.NoError(t, err)
+		defer sub.Unsubscribe()
+
+		require.Len(t, node.subscriptions(), 1)
+		assert.Equal(t, []interface{}{"logs", map[string]interface{}{
+			"address": []interface{}{strings.ToLower(token.Hex())},
+			"topics":  []interface{}{[]interface{}{topic.Hex()}, nil},
+		}}, node.subscriptions()[0])
+
+		node.notify("logs", map[string]interface{}{"address": token, "topics": []common.Hash{topic}, "data": "0x01", "logIndex": "0x2"})
+		select {
+		case log := <-sub.C():
+			assert.Equal(t, token, log.Address)
+			assert.Equal(t, uint64(2), log.Index)
+			assert.Equal(t, []byte{0x01}, log.Data)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for log")
+		}
+	})
+
+	t.Run("pending transactions", func(t *testing.T) {
+		node := newFakeStreamNode(t, nil)
+		c := newWSClient(t, node)
+
+		sub, err := c.SubscribePendingTransactions(ctx)
+		require.NoError(t, err)
+		defer sub.Unsubscribe()
+
+		hash := common.HexToHash("0x1234")
+		node.notify("newPendingTransactions", hash)
+		select {
+		case got := <-sub.C():
+			assert.Equal(t, hash, got)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for transaction")
+		}
+	})
+
+	t.Run("unsubscribes on context cancel", func(t *testing.T) {
+		node := newFakeStreamNode(t, nil)
+		c := newWSClient(t, node)
+
+		subCtx, cancel := context.WithCancel(ctx)
+		sub, err := c.SubscribeNewHeads(subCtx)
+		require.NoError(t, err)
+		node.waitForSubscriptions(t, 1)
+
+		cancel()
+		select {
+		case _, ok := <-sub.C():
+			assert.False(t, ok)
+		case <-time.After(5 * time.Second):
+			t.Fatal("subscription channel not closed")
+		}
+		assert.NoError(t, sub.Err())
+		node.waitForSubscriptions(t, 0)
+	})
+
+	t.Run("resubscribes after reconnect", func(t *testing.T) {
+		node := newFakeStreamNode(t, nil)
+		c := newWSClient(t, node)
+
+		sub, err := c.SubscribeNewHeads(ctx)
+		require.NoError(t, err)
+		defer sub.Unsubscribe()
+
+		node.dropConnections()
+		assert.Eventually(t, func() bool { return node.dialCount() == 2 }, 5*time.Second, 5*time.Millisecond)
+		node.waitForSubscriptions(t, 1)
+
+		node.notify("newHeads", map[string]interface{}{"number": "0x20"})
+		select {
+		case head := <-sub.C():
+			assert.Equal(t, uint64(32), head.Number)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for head after reconnect")
+		}
+	})
+
+	t.Run("close ends subscriptions", func(t *testing.T) {
+		node := newFakeStreamNode(t, nil)
+		c := newWSClient(t, node)
+
+		sub, err := c.SubscribeNewHeads(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, c.Close())
+		select {
+		case _, ok := <-sub.C():
+			assert.False(t, ok)
+		case <-time.After(5 * time.Second):
+			t.Fatal("subscription channel not closed")
+		}
+		assert.ErrorIs(t, sub.Err(), errStreamClosed)
+
+		_, err = c.GetBlockNumber(ctx)
+		assert.ErrorIs(t, err, errStreamClosed)
+	})
+
+	t.Run("not supported over HTTP", func(t *testing.T) {
+		_, err := New("http://127.0.0.1:0").SubscribeNewHeads(ctx)
+		assert.ErrorIs(t, err, ErrSubscriptionsNotSupported)
+	})
+}
@@ -11,6 +11,14 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
+// HashSigner is implemented by anything that can sign a 32-byte hash on behalf of an address.
+// *Signer satisfies it; wrappers such as policy enforcers or remote key stores can implement
+// it to be used wherever the SDK accepts a signer.
+type HashSigner interface {
+	Address() common.Address
+	Sign(hash common.Hash) (*Signature, error)
+}
+
 // Signer is a basic wrapper for managing ECDSA private key and provides signing functionality.
 type Signer struct {
 	privateKey *ecdsa.PrivateKey
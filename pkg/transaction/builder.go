@@ -1,11 +1,19 @@
 package transaction
 
 import (
+	"context"
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 )
 
+// GasEstimator estimates the gas limit a transaction needs.
+// *client.Client implements this interface.
+type GasEstimator interface {
+	EstimateGas(ctx context.Context, tx *Tx) (uint64, error)
+}
+
 // Builder provides a fluent interface for constructing transactions.
 // This makes it easier to build complex transactions with many optional fields.
 //
@@ -125,6 +133,30 @@ func (b *Builder) AddAccessListEntry(address common.Address, storageKeys []commo
 	return b
 }
 
+// AutoGas estimates the gas limit of the transaction built so far and sets it to the
+// estimate plus a safety margin, given as a fraction (0.2 adds 20%).
+// Set the sender (tx.From) and calls before calling AutoGas.
+//
+// Example usage:
+//
+//	builder, err := transaction.NewBuilder(big.NewInt(42424)).
+//	    AddCall(token, big.NewInt(0), transferData).
+//	    SetFeeToken(transaction.AlphaUSDAddress).
+//	    AutoGas(ctx, rpcClient, 0.2)
+func (b *Builder) AutoGas(ctx context.Context, estimator GasEstimator, margin float64) (*Builder, error) {
+	if margin < 0 {
+		return b, fmt.Errorf("gas margin must not be negative, got %v", margin)
+	}
+
+	estimate, err := estimator.EstimateGas(ctx, b.tx)
+	if err != nil {
+		return b, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+
+	b.tx.Gas = estimate + uint64(float64(estimate)*margin)
+	return b, nil
+}
+
 // Build returns the constructed transaction.
 // Note: This does not validate the transaction. Call Validate() separately if needed.
 func (b *Builder) Build() *Tx {
@@ -1,6 +1,8 @@
 package transaction
 
 import (
+	"context"
+	"errors"
 	"math/big"
 	"testing"
 
@@ -169,3 +171,44 @@ func TestTransactionBuilder_MultipleCallsAndAccessList(t *testing.T) {
 	assert.Equal(t, addr1, tx.AccessList[0].Address)
 	assert.Equal(t, addr3, tx.AccessList[1].Address)
 }
+
+// fakeGasEstimator returns a fixed estimate and records the transaction it was given.
+type fakeGasEstimator struct {
+	estimate uint64
+	err      error
+	tx       *Tx
+}
+
+func (f *fakeGasEstimator) EstimateGas(_ context.Context, tx *Tx) (uint64, error) {
+	f.tx = tx
+	return f.estimate, f.err
+}
+
+func TestTransactionBuilder_AutoGas(t *testing.T) {
+	t.Run("applies margin", func(t *testing.T) {
+		estimator := &fakeGasEstimator{estimate: 50000}
+		builder := NewBuilder(big.NewInt(42424)).
+			AddCall(common.HexToAddress("0x1234567890123456789012345678901234567890"), nil, nil)
+
+		builder, err := builder.AutoGas(context.Background(), estimator, 0.2)
+		assert.NoError(t, err)
+
+		tx := builder.Build()
+		assert.Equal(t, uint64(60000), tx.Gas)
+		assert.Same(t, tx, estimator.tx)
+	})
+
+	t.Run("estimator error", func(t *testing.T) {
+		estimator := &fakeGasEstimator{err: errors.New("execution reverted")}
+
+		builder, err := NewBuilder(big.NewInt(42424)).SetGas(21000).AutoGas(context.Background(), estimator, 0.2)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "execution reverted")
+		assert.Equal(t, uint64(21000), builder.Build().Gas)
+	})
+
+	t.Run("negative margin", func(t *testing.T) {
+		_, err := NewBuilder(big.NewInt(42424)).AutoGas(context.Background(), &fakeGasEstimator{}, -1)
+		assert.Error(t, err)
+	})
+}
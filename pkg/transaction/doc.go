@@ -69,5 +69,16 @@
 //	tx.ValidAfter = uint64(time.Now().Unix())             // Activate now
 //	tx.ValidBefore = uint64(time.Now().Add(1 * time.Hour).Unix()) // Expire in 1 hour
 //
+// # Signing Policies
+//
+// Wrap a hot key in a PolicySigner to refuse transactions that break configured rules:
+//
+//	guarded := transaction.NewPolicySigner(hotKey, transaction.Policy{
+//		ChainID:   big.NewInt(transaction.ChainIDTempo),
+//		FeeTokens: []common.Address{transaction.AlphaUSDAddress},
+//		MaxFee:    big.NewInt(1_000_000),
+//	})
+//	err := transaction.SignTransaction(tx, guarded) // errors.Is(err, transaction.ErrPolicyViolation)
+//
 // For more details on the TempoTransaction specification, see the Tempo documentation.
 package transaction
@@ -20,4 +20,7 @@ var (
 
 	// ErrInvalidTransactionType is returned when a transaction has an unexpected type prefix.
 	ErrInvalidTransactionType = errors.New("invalid transaction type")
+
+	// ErrPolicyViolation is returned by PolicySigner when a transaction breaks one of its rules.
+	ErrPolicyViolation = errors.New("signing policy violation")
 )
@@ -0,0 +1,261 @@
+package transaction
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tempoxyz/tempo-go/pkg/signer"
+)
+
+// Policy describes the rules a PolicySigner enforces before it signs a transaction.
+// A zero-valued field disables the corresponding rule.
+type Policy struct {
+	// ChainID is the only chain ID the signer will sign for.
+	ChainID *big.Int
+
+	// FeeTokens is the allowlist of fee tokens. The zero address (native token)
+	// is only accepted if it is listed explicitly.
+	FeeTokens []common.Address
+
+	// MaxFee caps the worst-case fee, MaxFeePerGas * Gas, in fee token units.
+	MaxFee *big.Int
+
+	// Targets is the allowlist of call targets. Contract creations are rejected
+	// whenever an allowlist is configured.
+	Targets []common.Address
+
+	// MaxValidity caps how long a signed transaction may stay valid, measured from
+	// the later of now and ValidAfter until ValidBefore. Transactions without a
+	// ValidBefore are rejected when this is set.
+	MaxValidity time.Duration
+
+	// DailyValueLimit caps the total value signed per UTC day: the native call value
+	// plus the amounts of TIP-20 transfer and transferWithMemo calls to the tokens in
+	// FeeTokens, or to any TIP-20 token if FeeTokens is empty. Amounts of different
+	// tokens are added as is, which suits tokens with the same decimals such as USD
+	// stablecoins. It only applies to sender signatures, since a fee payer does not
+	// spend the call value.
+	DailyValueLimit *big.Int
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// PolicySigner wraps another signer and refuses to sign transactions that break its Policy.
+// It only signs through SignTransaction and AddFeePayerSignature; signing a bare hash is
+// refused because the transaction behind it cannot be checked.
+//
+// Example usage:
+//
+//	guarded := transaction.NewPolicySigner(hotKey, transaction.Policy{
+//	    ChainID:     big.NewInt(transaction.ChainIDTempo),
+//	    FeeTokens:   []common.Address{transaction.AlphaUSDAddress},
+//	    MaxValidity: time.Hour,
+//	})
+//
+//	err := transaction.AddFeePayerSignature(tx, guarded)
+//	if errors.Is(err, transaction.ErrPolicyViolation) {
+//	    // reject the sponsorship request
+//	}
+//
+// PolicySigner is safe for concurrent use.
+type PolicySigner struct {
+	signer signer.HashSigner
+	policy Policy
+
+	mu       sync.Mutex
+	spentDay string
+	spent    *big.Int
+}
+
+// NewPolicySigner creates a PolicySigner that enforces policy in front of sgn.
+func NewPolicySigner(sgn signer.HashSigner, policy Policy) *PolicySigner {
+	if policy.Now == nil {
+		policy.Now = time.Now
+	}
+	return &PolicySigner{
+		signer: sgn,
+		policy: policy,
+		spent:  big.NewInt(0),
+	}
+}
+
+// Address returns the address of the wrapped signer.
+func (p *PolicySigner) Address() common.Address {
+	return p.signer.Address()
+}
+
+// Sign always fails: a bare hash cannot be checked against the policy.
+func (p *PolicySigner) Sign(hash common.Hash) (*signer.Signature, error) {
+	return nil, fmt.Errorf("%w: refusing to sign hash %s without its transaction", ErrPolicyViolation, hash.Hex())
+}
+
+// SignTx checks the transaction against the policy and signs its sender sign payload.
+func (p *PolicySigner) SignTx(tx *Tx) (*signer.Signature, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.policy.Now()
+	if err := p.check(tx, now); err != nil {
+		return nil, err
+	}
+
+	value := p.spentValue(tx)
+	day := now.UTC().Format(time.DateOnly)
+	if p.policy.DailyValueLimit != nil {
+		if day != p.spentDay {
+			p.spentDay = day
+			p.spent = big.NewInt(0)
+		}
+		total := new(big.Int).Add(p.spent, value)
+		if total.Cmp(p.policy.DailyValueLimit) > 0 {
+			return nil, fmt.Errorf("%w: daily value limit %s exceeded (already signed %s, transaction value %s)",
+				ErrPolicyViolation, p.policy.DailyValueLimit, p.spent, value)
+		}
+	}
+
+	hash, err := GetSignPayload(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sign payload: %w", err)
+	}
+
+	sig, err := p.signer.Sign(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.policy.DailyValueLimit != nil {
+		p.spent.Add(p.spent, value)
+	}
+
+	return sig, nil
+}
+
+// SignFeePayerTx checks the transaction against the policy and signs its fee payer sign payload.
+func (p *PolicySigner) SignFeePayerTx(tx *Tx, sender common.Address) (*signer.Signature, error) {
+	if err := p.check(tx, p.policy.Now()); err != nil {
+		return nil, err
+	}
+
+	hash, err := GetFeePayerSignPayload(tx, sender)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee payer sign payload: %w", err)
+	}
+
+	return p.signer.Sign(hash)
+}
+
+// check applies every stateless rule of the policy to tx.
+func (p *PolicySigner) check(tx *Tx, now time.Time) error {
+	policy := p.policy
+
+	if policy.ChainID != nil && (tx.ChainID == nil || tx.ChainID.Cmp(policy.ChainID) != 0) {
+		return fmt.Errorf("%w: chain ID %v is not allowed (want %s)", ErrPolicyViolation, tx.ChainID, policy.ChainID)
+	}
+
+	if len(policy.FeeTokens) > 0 && !containsAddress(policy.FeeTokens, tx.FeeToken) {
+		return fmt.Errorf("%w: fee token %s is not allowed", ErrPolicyViolation, tx.FeeToken.Hex())
+	}
+
+	if policy.MaxFee != nil {
+		maxFeePerGas := tx.MaxFeePerGas
+		if maxFeePerGas == nil {
+			maxFeePerGas = big.NewInt(0)
+		}
+		fee := new(big.Int).Mul(maxFeePerGas, new(big.Int).SetUint64(tx.Gas))
+		if fee.Cmp(policy.MaxFee) > 0 {
+			return fmt.Errorf("%w: max fee %s exceeds cap %s", ErrPolicyViolation, fee, policy.MaxFee)
+		}
+	}
+
+	if len(policy.Targets) > 0 {
+		for i, call := range tx.Calls {
+			if call.To == nil {
+				return fmt.Errorf("%w: call %d is a contract creation", ErrPolicyViolation, i)
+			}
+			if !containsAddress(policy.Targets, *call.To) {
+				return fmt.Errorf("%w: call %d target %s is not allowed", ErrPolicyViolation, i, call.To.Hex())
+			}
+		}
+	}
+
+	if policy.MaxValidity > 0 {
+		if tx.ValidBefore == 0 {
+			return fmt.Errorf("%w: transaction has no expiry (validBefore)", ErrPolicyViolation)
+		}
+		start := uint64(now.Unix())
+		if tx.ValidAfter > start {
+			start = tx.ValidAfter
+		}
+		if tx.ValidBefore <= start {
+			return fmt.Errorf("%w: validity window ends at %d, before it starts at %d", ErrPolicyViolation,
+				tx.ValidBefore, start)
+		}
+		if tx.ValidBefore-start > uint64(policy.MaxValidity/time.Second) {
+			return fmt.Errorf("%w: validity window of %ds exceeds %s", ErrPolicyViolation,
+				tx.ValidBefore-start, policy.MaxValidity)
+		}
+	}
+
+	return nil
+}
+
+// spentValue sums the native value of every call in tx and the amounts it transfers
+// of the tokens counted by DailyValueLimit.
+func (p *PolicySigner) spentValue(tx *Tx) *big.Int {
+	total := big.NewInt(0)
+	for _, call := range tx.Calls {
+		if call.Value != nil {
+			total.Add(total, call.Value)
+		}
+		if call.To != nil && p.countsToken(*call.To) {
+			if amount := tip20TransferAmount(call.Data); amount != nil {
+				total.Add(total, amount)
+			}
+		}
+	}
+	return total
+}
+
+// countsToken reports whether transfers of token count against DailyValueLimit.
+func (p *PolicySigner) countsToken(token common.Address) bool {
+	if len(p.policy.FeeTokens) > 0 {
+		return containsAddress(p.policy.FeeTokens, token)
+	}
+	return bytes.HasPrefix(token.Bytes(), tip20AddressPrefix)
+}
+
+var (
+	// tip20AddressPrefix is the address prefix shared by TIP-20 tokens.
+	tip20AddressPrefix = common.FromHex("0x20c000000000000000000000")
+
+	transferSelector         = crypto.Keccak256([]byte("transfer(address,uint256)"))[:4]
+	transferWithMemoSelector = crypto.Keccak256([]byte("transferWithMemo(address,uint256,bytes32)"))[:4]
+)
+
+// tip20TransferAmount returns the amount of a TIP-20 transfer or transferWithMemo
+// call, or nil if data is neither. Both take the amount as their second argument.
+func tip20TransferAmount(data []byte) *big.Int {
+	if len(data) < 4+2*32 {
+		return nil
+	}
+	if !bytes.Equal(data[:4], transferSelector) && !bytes.Equal(data[:4], transferWithMemoSelector) {
+		return nil
+	}
+	return new(big.Int).SetBytes(data[4+32 : 4+2*32])
+}
+
+// containsAddress reports whether addr is in list.
+func containsAddress(list []common.Address, addr common.Address) bool {
+	for _, a := range list {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
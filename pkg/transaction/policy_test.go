@@ -0,0 +1,235 @@
+package transaction
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempoxyz/tempo-go/pkg/signer"
+)
+
+var (
+	policyTarget = common.HexToAddress("0x1234567890123456789012345678901234567890")
+	policyNow    = time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+)
+
+func newPolicyTestTx() *Tx {
+	return NewBuilder(big.NewInt(ChainIDTempo)).
+		SetGas(100000).
+		SetMaxFeePerGas(big.NewInt(10)).
+		SetFeeToken(AlphaUSDAddress).
+		SetValidBefore(uint64(policyNow.Add(10*time.Minute).Unix())).
+		AddCall(policyTarget, big.NewInt(100), nil).
+		Build()
+}
+
+func newTestPolicySigner(t *testing.T, policy Policy) (*PolicySigner, *signer.Signer) {
+	t.Helper()
+	inner, err := signer.NewSigner(testSenderKey)
+	require.NoError(t, err)
+	if policy.Now == nil {
+		policy.Now = func() time.Time { return policyNow }
+	}
+	return NewPolicySigner(inner, policy), inner
+}
+
+func TestPolicySigner_Allows(t *testing.T) {
+	ps, inner := newTestPolicySigner(t, Policy{
+		ChainID:         big.NewInt(ChainIDTempo),
+		FeeTokens:       []common.Address{AlphaUSDAddress},
+		MaxFee:          big.NewInt(1000000),
+		Targets:         []common.Address{policyTarget},
+		MaxValidity:     time.Hour,
+		DailyValueLimit: big.NewInt(1000),
+	})
+
+	tx := newPolicyTestTx()
+	require.NoError(t, SignTransaction(tx, ps))
+
+	sender, err := VerifySignature(tx)
+	require.NoError(t, err)
+	assert.Equal(t, inner.Address(), sender)
+	assert.Equal(t, inner.Address(), tx.From)
+}
+
+func TestPolicySigner_Rejects(t *testing.T) {
+	otherToken := common.HexToAddress("0x20c0000000000000000000000000000000000002")
+	otherTarget := common.HexToAddress("0x9999999999999999999999999999999999999999")
+
+	tests := []struct {
+		name   string
+		policy Policy
+		modify func(tx *Tx)
+	}{
+		{
+			name:   "wrong chain ID",
+			policy: Policy{ChainID: big.NewInt(ChainIDTempoTestnet)},
+		},
+		{
+			name:   "fee token not allowed",
+			policy: Policy{FeeTokens: []common.Address{AlphaUSDAddress}},
+			modify: func(tx *Tx) { tx.FeeToken = otherToken },
+		},
+		{
+			name:   "max fee exceeded",
+			policy: Policy{MaxFee: big.NewInt(999999)},
+		},
+		{
+			name:   "target not allowed",
+			policy: Policy{Targets: []common.Address{policyTarget}},
+			modify: func(tx *Tx) { tx.Calls = append(tx.Calls, Call{To: &otherTarget, Value: big.NewInt(0)}) },
+		},
+		{
+			name:   "contract creation with target allowlist",
+			policy: Policy{Targets: []common.Address{policyTarget}},
+			modify: func(tx *Tx) { tx.Calls[0].To = nil },
+		},
+		{
+			name:   "no expiry",
+			policy: Policy{MaxValidity: time.Hour},
+			modify: func(tx *Tx) { tx.ValidBefore = 0 },
+		},
+		{
+			name:   "validity window too long",
+			policy: Policy{MaxValidity: time.Minute},
+		},
+		{
+			name:   "validity window too long after validAfter",
+			policy: Policy{MaxValidity: time.Hour},
+			modify: func(tx *Tx) {
+				tx.ValidAfter = uint64(policyNow.Add(time.Hour).Unix())
+				tx.ValidBefore = uint64(policyNow.Add(3 * time.Hour).Unix())
+			},
+		},
+		{
+			name:   "expired",
+			policy: Policy{MaxValidity: time.Hour},
+			modify: func(tx *Tx) { tx.ValidBefore = uint64(policyNow.Add(-time.Minute).Unix()) },
+		},
+		{
+			name:   "validAfter after validBefore",
+			policy: Policy{MaxValidity: time.Hour},
+			modify: func(tx *Tx) {
+				tx.ValidAfter = uint64(policyNow.Add(time.Hour).Unix())
+				tx.ValidBefore = uint64(policyNow.Add(30 * time.Minute).Unix())
+			},
+		},
+		{
+			name:   "daily value limit exceeded",
+			policy: Policy{DailyValueLimit: big.NewInt(99)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps, _ := newTestPolicySigner(t, tt.policy)
+			tx := newPolicyTestTx()
+			if tt.modify != nil {
+				tt.modify(tx)
+			}
+
+			err := SignTransaction(tx, ps)
+			assert.True(t, errors.Is(err, ErrPolicyViolation), "got %v", err)
+			assert.Nil(t, tx.Signature)
+		})
+	}
+}
+
+func TestPolicySigner_DailyValueLimit(t *testing.T) {
+	now := policyNow
+	ps, _ := newTestPolicySigner(t, Policy{
+		DailyValueLimit: big.NewInt(250),
+		Now:             func() time.Time { return now },
+	})
+
+	require.NoError(t, SignTransaction(newPolicyTestTx(), ps))
+	require.NoError(t, SignTransaction(newPolicyTestTx(), ps))
+
+	err := SignTransaction(newPolicyTestTx(), ps)
+	assert.True(t, errors.Is(err, ErrPolicyViolation))
+
+	// The limit resets at the start of the next UTC day.
+	now = now.Add(24 * time.Hour)
+	assert.NoError(t, SignTransaction(newPolicyTestTx(), ps))
+}
+
+func TestPolicySigner_DailyValueLimitTokenTransfers(t *testing.T) {
+	recipient := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	otherToken := common.HexToAddress("0x20c0000000000000000000000000000000000002")
+	transfer := func(token common.Address, selector []byte, amount int64, extra ...byte) *Tx {
+		data := append([]byte(nil), selector...)
+		data = append(data, common.LeftPadBytes(recipient.Bytes(), 32)...)
+		data = append(data, common.LeftPadBytes(big.NewInt(amount).Bytes(), 32)...)
+		data = append(data, extra...)
+		tx := newPolicyTestTx()
+		tx.Calls = []Call{{To: &token, Value: big.NewInt(0), Data: data}}
+		return tx
+	}
+	memo := make([]byte, 32)
+
+	t.Run("fee tokens", func(t *testing.T) {
+		ps, _ := newTestPolicySigner(t, Policy{
+			FeeTokens:       []common.Address{AlphaUSDAddress},
+			DailyValueLimit: big.NewInt(250),
+		})
+
+		require.NoError(t, SignTransaction(transfer(AlphaUSDAddress, transferSelector, 200), ps))
+		// Transfers of tokens outside FeeTokens are not counted.
+		require.NoError(t, SignTransaction(transfer(otherToken, transferSelector, 1000), ps))
+
+		err := SignTransaction(transfer(AlphaUSDAddress, transferWithMemoSelector, 100, memo...), ps)
+		assert.True(t, errors.Is(err, ErrPolicyViolation), "got %v", err)
+	})
+
+	t.Run("any TIP-20 token", func(t *testing.T) {
+		ps, _ := newTestPolicySigner(t, Policy{DailyValueLimit: big.NewInt(250)})
+
+		require.NoError(t, SignTransaction(transfer(otherToken, transferWithMemoSelector, 200, memo...), ps))
+		err := SignTransaction(transfer(AlphaUSDAddress, transferSelector, 100), ps)
+		assert.True(t, errors.Is(err, ErrPolicyViolation), "got %v", err)
+	})
+}
+
+func TestPolicySigner_FeePayer(t *testing.T) {
+	sender, err := signer.NewSigner(testSenderKey)
+	require.NoError(t, err)
+	feePayer, err := signer.NewSigner(testFeePayerKey)
+	require.NoError(t, err)
+
+	ps := NewPolicySigner(feePayer, Policy{
+		FeeTokens:       []common.Address{AlphaUSDAddress},
+		DailyValueLimit: big.NewInt(1),
+		Now:             func() time.Time { return policyNow },
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		tx := newPolicyTestTx()
+		require.NoError(t, SignTransaction(tx, sender))
+		require.NoError(t, AddFeePayerSignature(tx, ps))
+
+		_, payer, err := VerifyDualSignatures(tx)
+		require.NoError(t, err)
+		assert.Equal(t, feePayer.Address(), payer)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		tx := newPolicyTestTx()
+		tx.FeeToken = common.Address{}
+		require.NoError(t, SignTransaction(tx, sender))
+
+		err := AddFeePayerSignature(tx, ps)
+		assert.True(t, errors.Is(err, ErrPolicyViolation))
+		assert.Nil(t, tx.FeePayerSignature)
+	})
+}
+
+func TestPolicySigner_RefusesBareHash(t *testing.T) {
+	ps, _ := newTestPolicySigner(t, Policy{})
+
+	_, err := ps.Sign(common.HexToHash("0x01"))
+	assert.True(t, errors.Is(err, ErrPolicyViolation))
+}
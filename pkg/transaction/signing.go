@@ -37,24 +37,48 @@ func GetFeePayerSignPayload(tx *Tx, sender common.Address) (common.Hash, error)
 	return ComputeHash(serialized)
 }
 
+// TxSigner is implemented by signers that need to inspect the decoded transaction
+// before anything is hashed, such as PolicySigner. SignTransaction and
+// AddFeePayerSignature call these methods instead of Sign when they are available.
+type TxSigner interface {
+	signer.HashSigner
+
+	// SignTx returns the sender signature over the transaction's sign payload.
+	SignTx(tx *Tx) (*signer.Signature, error)
+
+	// SignFeePayerTx returns the fee payer signature over the transaction's fee payer sign payload.
+	SignFeePayerTx(tx *Tx, sender common.Address) (*signer.Signature, error)
+}
+
 // SignTransaction signs a transaction with the sender's private key.
 // This creates the sender signature envelope and adds it to the transaction.
-func SignTransaction(tx *Tx, sgn *signer.Signer) error {
+// If sgn implements TxSigner, the transaction is handed to it before hashing.
+func SignTransaction(tx *Tx, sgn signer.HashSigner) error {
 	// Validate transaction before signing
 	if err := tx.Validate(); err != nil {
 		return err
 	}
 
-	// Get the sign payload
-	hash, err := GetSignPayload(tx)
-	if err != nil {
-		return fmt.Errorf("failed to get sign payload: %w", err)
-	}
+	var sig *signer.Signature
+	if txSigner, ok := sgn.(TxSigner); ok {
+		s, err := txSigner.SignTx(tx)
+		if err != nil {
+			return fmt.Errorf("failed to sign transaction: %w", err)
+		}
+		sig = s
+	} else {
+		// Get the sign payload
+		hash, err := GetSignPayload(tx)
+		if err != nil {
+			return fmt.Errorf("failed to get sign payload: %w", err)
+		}
 
-	// Sign the hash
-	sig, err := sgn.Sign(hash)
-	if err != nil {
-		return fmt.Errorf("failed to sign transaction: %w", err)
+		// Sign the hash
+		s, err := sgn.Sign(hash)
+		if err != nil {
+			return fmt.Errorf("failed to sign transaction: %w", err)
+		}
+		sig = s
 	}
 
 	// Create signature envelope (secp256k1 type)
@@ -66,7 +90,8 @@ func SignTransaction(tx *Tx, sgn *signer.Signer) error {
 
 // AddFeePayerSignature adds the fee payer signature to a transaction.
 // The transaction must already have a sender signature.
-func AddFeePayerSignature(tx *Tx, sgn *signer.Signer) error {
+// If sgn implements TxSigner, the transaction is handed to it before hashing.
+func AddFeePayerSignature(tx *Tx, sgn signer.HashSigner) error {
 	// Ensure the transaction has a sender signature
 	if tx.Signature == nil {
 		return ErrMissingSenderSignature
@@ -89,6 +114,15 @@ func AddFeePayerSignature(tx *Tx, sgn *signer.Signer) error {
 		tx.From = sender
 	}
 
+	if txSigner, ok := sgn.(TxSigner); ok {
+		sig, err := txSigner.SignFeePayerTx(tx, sender)
+		if err != nil {
+			return fmt.Errorf("failed to sign as fee payer: %w", err)
+		}
+		tx.FeePayerSignature = sig
+		return nil
+	}
+
 	hash, err := GetFeePayerSignPayload(tx, sender)
 	if err != nil {
 		return fmt.Errorf("failed to get fee payer sign payload: %w", err)
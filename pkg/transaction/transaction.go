@@ -54,6 +54,9 @@ type AccessTuple struct {
 
 // Constants
 const (
+	// TxTypeTempo is the EIP-2718 type byte of a TempoTransaction.
+	TxTypeTempo = 0x76
+
 	// SignatureTypeSecp256k1 is the signature type for standard ECDSA signatures
 	SignatureTypeSecp256k1 = "secp256k1"
 
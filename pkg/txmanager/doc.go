@@ -0,0 +1,53 @@
+// Package txmanager provides a managed sender for Tempo transactions.
+//
+// A Manager signs and broadcasts transactions and tracks each one until it is
+// included, dropped or expired. Transactions that stay pending for too long are
+// resubmitted at the same (NonceKey, Nonce) with higher fees, and pending
+// transactions can be cancelled by replacing them with a no-op call.
+//
+// # Basic Usage
+//
+//	rpc := client.New("https://rpc.testnet.tempo.xyz")
+//	manager, err := txmanager.New(rpc, txmanager.Config{
+//		Signer: sgn,
+//		Bump:   txmanager.BumpPolicy{Interval: 30 * time.Second, Percent: 10},
+//		OnStateChange: func(e txmanager.Event) {
+//			log.Printf("%s: %s (%s)", e.Tx.ID().Hex(), e.State, e.Hash.Hex())
+//		},
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	go manager.Run(ctx)
+//
+//	mtx, err := manager.Send(ctx, tx)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	receipt, err := mtx.Wait(ctx)
+//
+// # Sponsored Transactions
+//
+// Transactions passed to Send with AwaitingFeePayer set are co-signed by
+// Config.FeePayer. Every bump and cancellation is a new transaction, so each one
+// gets a fresh fee payer signature.
+//
+// # Outbox
+//
+// Set Config.Store to persist every signed version before it is broadcast.
+// FileStore appends JSON lines to a local file; MemoryStore keeps entries in
+// memory. After a restart, Replay re-broadcasts the pending transactions that are
+// still valid and resumes tracking them:
+//
+//	store, err := txmanager.NewFileStore("outbox.jsonl")
+//	manager, err := txmanager.New(rpc, txmanager.Config{Signer: sgn, Store: store})
+//	pending, err := manager.Replay(ctx)
+//
+// # Nonces
+//
+// Set Config.Nonces to a client.NonceManager to assign nonces automatically.
+// Rejected sends release their nonce, and expired transactions hand theirs back
+// so the nonce lane has no gaps. A send that fails without a rejection, such as on
+// a timeout, keeps its nonce: Send returns an error wrapping ErrBroadcastUncertain
+// and the transaction stays pending.
+package txmanager
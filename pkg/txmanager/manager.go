@@ -0,0 +1,535 @@
+package txmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tempoxyz/tempo-go/pkg/client"
+	"github.com/tempoxyz/tempo-go/pkg/signer"
+	"github.com/tempoxyz/tempo-go/pkg/transaction"
+)
+
+const (
+	defaultPollInterval = 2 * time.Second
+	defaultBumpInterval = 30 * time.Second
+	defaultBumpPercent  = 10
+)
+
+// Backend is the subset of *client.Client used by the manager.
+type Backend interface {
+	SendTransaction(ctx context.Context, tx *transaction.Tx) (common.Hash, error)
+	GetTransactionReceipt(ctx context.Context, txHash common.Hash) (*client.Receipt, error)
+	GetNonce(ctx context.Context, address common.Address, nonceKey *big.Int, block client.BlockSelector) (uint64, error)
+}
+
+// BumpPolicy controls how the fees of stuck transactions are raised.
+type BumpPolicy struct {
+	// Interval is how long a transaction may stay pending after its last broadcast
+	// before it is resubmitted with higher fees. Default: 30s.
+	Interval time.Duration
+
+	// Percent is the increase applied to both MaxFeePerGas and MaxPriorityFeePerGas
+	// on every bump. Default: 10.
+	Percent int64
+
+	// MaxFeePerGas caps the bumped MaxFeePerGas. Nil means no cap.
+	MaxFeePerGas *big.Int
+
+	// MaxBumps is the maximum number of bumps per transaction. Zero means no limit.
+	MaxBumps int
+}
+
+// Config configures a Manager.
+type Config struct {
+	// Signer signs every version of the managed transactions. Required.
+	Signer signer.HashSigner
+
+	// FeePayer co-signs sponsored transactions, which are transactions passed to Send
+	// with AwaitingFeePayer set or a fee payer signature attached. Every bump and
+	// cancellation gets a fresh fee payer signature.
+	FeePayer signer.HashSigner
+
+	// Store optionally persists every signed version before it is broadcast, so that
+	// pending transactions can be recovered with Replay after a restart.
+	Store Store
+
+	// Nonces optionally assigns (NonceKey, Nonce) pairs to new transactions. When nil,
+	// transactions are sent with the nonce fields they already have.
+	Nonces *client.NonceManager
+
+	// Bump controls fee bumping of stuck transactions.
+	Bump BumpPolicy
+
+	// PollInterval is how often Run checks pending transactions. Default: 2s.
+	PollInterval time.Duration
+
+	// OnStateChange is called with every state change and every resubmission.
+	// It is called synchronously and must not block.
+	OnStateChange func(Event)
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Manager sends transactions and tracks them until they are included, dropped or
+// expired, bumping fees of stuck transactions along the way.
+// Manager is safe for concurrent use.
+type Manager struct {
+	backend Backend
+	cfg     Config
+
+	mu      sync.Mutex
+	pending []*ManagedTx
+}
+
+// New creates a transaction manager.
+func New(backend Backend, cfg Config) (*Manager, error) {
+	if cfg.Signer == nil {
+		return nil, errors.New("txmanager: signer is required")
+	}
+	if cfg.Bump.Interval <= 0 {
+		cfg.Bump.Interval = defaultBumpInterval
+	}
+	if cfg.Bump.Percent <= 0 {
+		cfg.Bump.Percent = defaultBumpPercent
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return &Manager{backend: backend, cfg: cfg}, nil
+}
+
+// Send signs and broadcasts a transaction and starts tracking it.
+// The transaction's fee and call fields are used as given; if a NonceManager is
+// configured, the nonce key and nonce are assigned from it.
+//
+// If the node rejects the transaction, Send returns the error and releases the
+// nonce. If the broadcast fails otherwise, for example on a timeout or an HTTP 5xx,
+// the transaction may have reached the node: it keeps its nonce and stays pending,
+// to be resubmitted by Poll or Replay, and Send returns it along with an error
+// wrapping ErrBroadcastUncertain.
+func (m *Manager) Send(ctx context.Context, tx *transaction.Tx) (*ManagedTx, error) {
+	sponsored := tx.AwaitingFeePayer || tx.FeePayerSignature != nil
+	if sponsored && m.cfg.FeePayer == nil {
+		return nil, errors.New("txmanager: sponsored transaction requires a fee payer signer")
+	}
+
+	unsigned := tx.Clone()
+	var nonce client.Nonce
+	if m.cfg.Nonces != nil {
+		n, err := m.cfg.Nonces.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		nonce = n
+		unsigned.NonceKey = new(big.Int).Set(n.Key)
+		unsigned.Nonce = n.Nonce
+	}
+
+	var mtx *ManagedTx
+	signed, err := m.sign(unsigned, sponsored)
+	if err == nil {
+		mtx, err = newManagedTx(signed, unsigned, sponsored, m.cfg.Now())
+	}
+	if err == nil {
+		err = m.saveTx(mtx)
+	}
+	if err == nil {
+		err = m.broadcast(ctx, signed)
+		if err != nil && !rejected(err) {
+			// The pending record stays in the store, so Replay broadcasts it again.
+			m.track(mtx)
+			m.emit(mtx, StatePending)
+			return mtx, fmt.Errorf("%w: %w", ErrBroadcastUncertain, err)
+		}
+		if err != nil && m.cfg.Store != nil {
+			// The error is the caller's to handle; the record only keeps Replay from
+			// broadcasting the transaction again.
+			m.save(mtx.id, signed, mtx.versions, sponsored, StateRejected)
+		}
+	}
+	if err != nil {
+		if m.cfg.Nonces != nil {
+			m.cfg.Nonces.HandleError(ctx, nonce, err)
+		}
+		return nil, err
+	}
+
+	m.track(mtx)
+	m.emit(mtx, StatePending)
+	return mtx, nil
+}
+
+// Replay recovers the pending transactions recorded in the store, typically after a
+// restart. Transactions that are still valid are broadcast again; a node that
+// already knows a transaction counts as success. Expired transactions are not
+// broadcast, but are tracked until Poll settles their final state.
+// Errors from individual broadcasts are returned after all entries are processed.
+func (m *Manager) Replay(ctx context.Context) ([]*ManagedTx, error) {
+	if m.cfg.Store == nil {
+		return nil, errors.New("txmanager: no store configured")
+	}
+	entries, err := m.cfg.Store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load outbox: %w", err)
+	}
+
+	var replayed []*ManagedTx
+	var errs []error
+	for _, entry := range entries {
+		if entry.State.Final() {
+			continue
+		}
+		mtx, err := managedTxFromEntry(entry, m.cfg.Now())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("outbox entry %s: %w", entry.ID.Hex(), err))
+			continue
+		}
+
+		if !mtx.unsigned.IsExpired(uint64(m.cfg.Now().Unix())) {
+			if err := m.broadcast(ctx, mtx.tx); err != nil && !errors.Is(err, client.ErrNonceTooLow) {
+				// A lower nonce means some version was included or the nonce was
+				// taken; Poll finds out which. Other errors are retried by bumps.
+				errs = append(errs, fmt.Errorf("transaction %s: %w", entry.ID.Hex(), err))
+			}
+		}
+		m.track(mtx)
+		replayed = append(replayed, mtx)
+	}
+	return replayed, errors.Join(errs...)
+}
+
+func (m *Manager) track(mtx *ManagedTx) {
+	m.mu.Lock()
+	m.pending = append(m.pending, mtx)
+	m.mu.Unlock()
+}
+
+// Cancel replaces a pending transaction with a no-op call to the sender at the same
+// (NonceKey, Nonce) and higher fees. The original may still be included if it wins
+// the race; the final state is StateIncluded or StateCancelled accordingly.
+// A cancellation whose broadcast outcome is unknown is tracked all the same, and the
+// error wraps ErrBroadcastUncertain.
+func (m *Manager) Cancel(ctx context.Context, mtx *ManagedTx) error {
+	mtx.mu.Lock()
+	if mtx.state != StatePending {
+		state := mtx.state
+		mtx.mu.Unlock()
+		return fmt.Errorf("txmanager: cannot cancel %s transaction", state)
+	}
+	sender := m.cfg.Signer.Address()
+	cancel := mtx.unsigned.Clone()
+	cancel.Calls = []transaction.Call{{To: &sender, Value: big.NewInt(0)}}
+	if !m.bumpFees(cancel) {
+		mtx.mu.Unlock()
+		return errors.New("txmanager: fees are at the cap, cannot replace transaction")
+	}
+	sponsored := mtx.sponsored
+	mtx.mu.Unlock()
+
+	err := m.replace(ctx, mtx, cancel, sponsored, true)
+	if err != nil && !errors.Is(err, ErrBroadcastUncertain) {
+		return err
+	}
+	m.emit(mtx, StateCancelling)
+	return err
+}
+
+// Run polls pending transactions every PollInterval until ctx is cancelled.
+// Errors from individual polls are retried on the next tick.
+func (m *Manager) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			m.Poll(ctx)
+		}
+	}
+}
+
+// Poll checks every pending transaction once: it records inclusions, detects dropped
+// and expired transactions, and bumps the fees of stuck ones.
+func (m *Manager) Poll(ctx context.Context) error {
+	m.mu.Lock()
+	pending := append([]*ManagedTx(nil), m.pending...)
+	m.mu.Unlock()
+
+	var errs []error
+	for _, mtx := range pending {
+		if err := m.poll(ctx, mtx); err != nil {
+			errs = append(errs, fmt.Errorf("transaction %s: %w", mtx.ID().Hex(), err))
+		}
+	}
+
+	m.mu.Lock()
+	active := m.pending[:0]
+	for _, mtx := range m.pending {
+		if !mtx.State().Final() {
+			active = append(active, mtx)
+		}
+	}
+	m.pending = active
+	m.mu.Unlock()
+
+	return errors.Join(errs...)
+}
+
+// Pending returns the transactions that are still being tracked.
+func (m *Manager) Pending() []*ManagedTx {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*ManagedTx(nil), m.pending...)
+}
+
+func (m *Manager) poll(ctx context.Context, mtx *ManagedTx) error {
+	snapshot := mtx.snapshot()
+
+	// Read the chain nonce before the receipts, so that a consumed nonce without any
+	// of our receipts reliably means another transaction took the slot.
+	chainNonce, err := m.backend.GetNonce(ctx, m.cfg.Signer.Address(), snapshot.unsigned.NonceKey, client.Latest)
+	if err != nil {
+		return err
+	}
+
+	for i := len(snapshot.versions) - 1; i >= 0; i-- {
+		version := snapshot.versions[i]
+		receipt, err := m.backend.GetTransactionReceipt(ctx, version.hash)
+		if errors.Is(err, client.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		state := StateIncluded
+		if version.cancel {
+			state = StateCancelled
+		}
+		return m.finish(mtx, state, version.hash, receipt)
+	}
+
+	now := m.cfg.Now()
+	nonce := client.Nonce{Key: snapshot.unsigned.NonceKey, Nonce: snapshot.unsigned.Nonce}
+	switch {
+	case chainNonce > snapshot.unsigned.Nonce:
+		if err := m.finish(mtx, StateDropped, common.Hash{}, nil); err != nil {
+			return err
+		}
+		if m.cfg.Nonces != nil {
+			return m.cfg.Nonces.Resync(ctx, nonce.Key)
+		}
+		return nil
+	case snapshot.unsigned.IsExpired(uint64(now.Unix())):
+		if m.cfg.Nonces != nil {
+			// The nonce was never consumed; hand it out again so the lane has no gap.
+			m.cfg.Nonces.Release(nonce)
+		}
+		return m.finish(mtx, StateExpired, common.Hash{}, nil)
+	case now.Sub(snapshot.lastSent) < m.cfg.Bump.Interval:
+		return nil
+	case m.cfg.Bump.MaxBumps > 0 && snapshot.bumps >= m.cfg.Bump.MaxBumps:
+		return nil
+	}
+
+	bumped := snapshot.unsigned.Clone()
+	if !m.bumpFees(bumped) {
+		return nil
+	}
+	err = m.replace(ctx, mtx, bumped, snapshot.sponsored, snapshot.cancelling)
+	if err != nil && !errors.Is(err, ErrBroadcastUncertain) {
+		return err
+	}
+	m.emit(mtx, snapshot.state)
+	return err
+}
+
+// replace signs and broadcasts a new version of a managed transaction. The version
+// is only discarded if the node rejects it.
+func (m *Manager) replace(ctx context.Context, mtx *ManagedTx, unsigned *transaction.Tx, sponsored, cancel bool) error {
+	signed, err := m.sign(unsigned, sponsored)
+	if err != nil {
+		return err
+	}
+	hash, err := signed.Hash()
+	if err != nil {
+		return err
+	}
+
+	mtx.mu.Lock()
+	versions := append(append([]version(nil), mtx.versions...), version{hash: hash, cancel: cancel})
+	state := mtx.state
+	mtx.mu.Unlock()
+	if cancel {
+		state = StateCancelling
+	}
+	if err := m.save(mtx.id, signed, versions, sponsored, state); err != nil {
+		return err
+	}
+	err = m.broadcast(ctx, signed)
+	if err != nil && rejected(err) {
+		// Drop the refused version from the store again.
+		m.saveTx(mtx)
+		return fmt.Errorf("failed to resubmit: %w", err)
+	}
+
+	// A version whose broadcast outcome is unknown may still be mined, so it is
+	// tracked like one that was sent.
+	mtx.mu.Lock()
+	mtx.tx = signed
+	mtx.unsigned = unsigned
+	mtx.versions = append(mtx.versions, version{hash: hash, cancel: cancel})
+	mtx.lastSent = m.cfg.Now()
+	mtx.bumps++
+	if cancel {
+		mtx.state = StateCancelling
+	}
+	mtx.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to resubmit: %w: %w", ErrBroadcastUncertain, err)
+	}
+	return nil
+}
+
+// rejected reports whether a broadcast error means the transaction was refused, by
+// the node or before it was sent. Transport failures, HTTP errors and rate limits
+// leave the outcome unknown.
+func rejected(err error) bool {
+	if errors.Is(err, client.ErrChainIDMismatch) || errors.Is(err, transaction.ErrNoSignature) ||
+		errors.Is(err, transaction.ErrInvalidTransaction) {
+		return true
+	}
+	var rpcErr *client.JSONRPCError
+	return errors.As(err, &rpcErr) && !errors.Is(err, client.ErrRateLimited)
+}
+
+// broadcast sends a signed transaction. A node that already has the transaction in
+// its pool counts as success.
+func (m *Manager) broadcast(ctx context.Context, signed *transaction.Tx) error {
+	if _, err := m.backend.SendTransaction(ctx, signed); err != nil && !errors.Is(err, client.ErrAlreadyKnown) {
+		return err
+	}
+	return nil
+}
+
+// saveTx records the current state of a managed transaction in the store.
+func (m *Manager) saveTx(mtx *ManagedTx) error {
+	mtx.mu.Lock()
+	signed, versions, sponsored, state := mtx.tx, mtx.versions, mtx.sponsored, mtx.state
+	mtx.mu.Unlock()
+	return m.save(mtx.id, signed, versions, sponsored, state)
+}
+
+// save records a signed version of a managed transaction in the store.
+func (m *Manager) save(id common.Hash, signed *transaction.Tx, versions []version, sponsored bool, state State) error {
+	if m.cfg.Store == nil {
+		return nil
+	}
+	raw, err := transaction.Serialize(signed, nil)
+	if err != nil {
+		return fmt.Errorf("failed to serialize transaction: %w", err)
+	}
+
+	entry := Entry{
+		ID:        id,
+		RawTx:     raw,
+		Versions:  make([]EntryVersion, len(versions)),
+		Sponsored: sponsored,
+		State:     state,
+		UpdatedAt: m.cfg.Now(),
+	}
+	for i, v := range versions {
+		entry.Versions[i] = EntryVersion{Hash: v.hash, Cancel: v.cancel}
+	}
+	if err := m.cfg.Store.Save(entry); err != nil {
+		return fmt.Errorf("failed to save outbox entry: %w", err)
+	}
+	return nil
+}
+
+// sign signs a copy of unsigned as the sender, and as the fee payer if sponsored.
+func (m *Manager) sign(unsigned *transaction.Tx, sponsored bool) (*transaction.Tx, error) {
+	signed := unsigned.Clone()
+	signed.AwaitingFeePayer = sponsored
+	if err := transaction.SignTransaction(signed, m.cfg.Signer); err != nil {
+		return nil, err
+	}
+	if sponsored {
+		if err := transaction.AddFeePayerSignature(signed, m.cfg.FeePayer); err != nil {
+			return nil, err
+		}
+	}
+	return signed, nil
+}
+
+// bumpFees raises the fees of tx by the bump percentage, respecting the cap.
+// Returns false if MaxFeePerGas is already at the cap.
+func (m *Manager) bumpFees(tx *transaction.Tx) bool {
+	maxFee := bumpValue(tx.MaxFeePerGas, m.cfg.Bump.Percent)
+	if limit := m.cfg.Bump.MaxFeePerGas; limit != nil && maxFee.Cmp(limit) > 0 {
+		if tx.MaxFeePerGas.Cmp(limit) >= 0 {
+			return false
+		}
+		maxFee = new(big.Int).Set(limit)
+	}
+
+	tip := bumpValue(tx.MaxPriorityFeePerGas, m.cfg.Bump.Percent)
+	if tip.Cmp(maxFee) > 0 {
+		tip = new(big.Int).Set(maxFee)
+	}
+	tx.MaxFeePerGas = maxFee
+	tx.MaxPriorityFeePerGas = tip
+	return true
+}
+
+// bumpValue returns v increased by percent, rounded up and by at least 1.
+func bumpValue(v *big.Int, percent int64) *big.Int {
+	bumped := new(big.Int).Mul(v, big.NewInt(100+percent))
+	bumped.Add(bumped, big.NewInt(99))
+	bumped.Div(bumped, big.NewInt(100))
+	if bumped.Cmp(v) <= 0 {
+		bumped.Add(v, big.NewInt(1))
+	}
+	return bumped
+}
+
+func (m *Manager) finish(mtx *ManagedTx, state State, hash common.Hash, receipt *client.Receipt) error {
+	mtx.mu.Lock()
+	if mtx.state.Final() {
+		mtx.mu.Unlock()
+		return nil
+	}
+	mtx.state = state
+	mtx.receipt = receipt
+	if hash != (common.Hash{}) {
+		mtx.includedHash = hash
+	}
+	close(mtx.done)
+	mtx.mu.Unlock()
+
+	m.emit(mtx, state)
+	return m.saveTx(mtx)
+}
+
+func (m *Manager) emit(mtx *ManagedTx, state State) {
+	if m.cfg.OnStateChange == nil {
+		return
+	}
+	m.cfg.OnStateChange(Event{
+		Tx:      mtx,
+		State:   state,
+		Hash:    mtx.Hash(),
+		Receipt: mtx.Receipt(),
+	})
+}
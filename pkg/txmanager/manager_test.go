@@ -0,0 +1,462 @@
+package txmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempoxyz/tempo-go/pkg/client"
+	"github.com/tempoxyz/tempo-go/pkg/signer"
+	"github.com/tempoxyz/tempo-go/pkg/transaction"
+)
+
+const (
+	senderKey   = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	feePayerKey = "0x59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d"
+)
+
+var recipient = common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+
+// fakeBackend records broadcast transactions and serves receipts and nonces.
+type fakeBackend struct {
+	mu       sync.Mutex
+	sent     []*transaction.Tx
+	receipts map[common.Hash]*client.Receipt
+	nonces   map[string]uint64
+	sendErr  error
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{
+		receipts: make(map[common.Hash]*client.Receipt),
+		nonces:   make(map[string]uint64),
+	}
+}
+
+func (b *fakeBackend) SendTransaction(_ context.Context, tx *transaction.Tx) (common.Hash, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.sendErr != nil {
+		return common.Hash{}, b.sendErr
+	}
+	b.sent = append(b.sent, tx)
+	return tx.Hash()
+}
+
+func (b *fakeBackend) GetTransactionReceipt(_ context.Context, hash common.Hash) (*client.Receipt, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if receipt, ok := b.receipts[hash]; ok {
+		return receipt, nil
+	}
+	return nil, client.ErrNotFound
+}
+
+func (b *fakeBackend) GetNonce(_ context.Context, _ common.Address, nonceKey *big.Int, _ client.BlockSelector) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.nonces[nonceKey.String()], nil
+}
+
+func (b *fakeBackend) setNonce(nonceKey int64, nonce uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nonces[big.NewInt(nonceKey).String()] = nonce
+}
+
+// include marks the sent transaction with the given hash as included.
+func (b *fakeBackend) include(hash common.Hash) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.receipts[hash] = &client.Receipt{TxHash: hash, Status: client.ReceiptStatusSuccessful}
+	for _, tx := range b.sent {
+		if h, _ := tx.Hash(); h == hash {
+			b.nonces[tx.NonceKey.String()] = tx.Nonce + 1
+		}
+	}
+}
+
+func (b *fakeBackend) lastSent() *transaction.Tx {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sent[len(b.sent)-1]
+}
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+type testEnv struct {
+	backend *fakeBackend
+	clock   *fakeClock
+	manager *Manager
+	events  []Event
+}
+
+func newTestEnv(t *testing.T, configure func(*Config)) *testEnv {
+	t.Helper()
+	sgn, err := signer.NewSigner(senderKey)
+	require.NoError(t, err)
+
+	env := &testEnv{
+		backend: newFakeBackend(),
+		clock:   &fakeClock{now: time.Unix(1700000000, 0)},
+	}
+	cfg := Config{
+		Signer:        sgn,
+		Bump:          BumpPolicy{Interval: time.Minute, Percent: 10},
+		OnStateChange: func(e Event) { env.events = append(env.events, e) },
+		Now:           env.clock.Now,
+	}
+	if configure != nil {
+		configure(&cfg)
+	}
+	env.manager, err = New(env.backend, cfg)
+	require.NoError(t, err)
+	return env
+}
+
+func (env *testEnv) states() []State {
+	states := make([]State, len(env.events))
+	for i, e := range env.events {
+		states[i] = e.State
+	}
+	return states
+}
+
+func newTx() *transaction.Tx {
+	return transaction.NewBuilder(big.NewInt(transaction.ChainIDTempoTestnet)).
+		SetGas(100000).
+		SetMaxFeePerGas(big.NewInt(1000)).
+		SetMaxPriorityFeePerGas(big.NewInt(100)).
+		AddCall(recipient, big.NewInt(0), []byte{0x01}).
+		Build()
+}
+
+func TestManager_Included(t *testing.T) {
+	env := newTestEnv(t, nil)
+	ctx := context.Background()
+
+	mtx, err := env.manager.Send(ctx, newTx())
+	require.NoError(t, err)
+	assert.Equal(t, StatePending, mtx.State())
+	assert.Len(t, env.manager.Pending(), 1)
+
+	require.NoError(t, env.manager.Poll(ctx))
+	assert.Equal(t, StatePending, mtx.State())
+
+	env.backend.include(mtx.ID())
+	require.NoError(t, env.manager.Poll(ctx))
+
+	receipt, err := mtx.Wait(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, mtx.ID(), receipt.TxHash)
+	assert.Equal(t, []State{StatePending, StateIncluded}, env.states())
+	assert.Empty(t, env.manager.Pending())
+}
+
+func TestManager_Bump(t *testing.T) {
+	env := newTestEnv(t, func(cfg *Config) {
+		cfg.Bump.MaxFeePerGas = big.NewInt(1150)
+	})
+	ctx := context.Background()
+
+	mtx, err := env.manager.Send(ctx, newTx())
+	require.NoError(t, err)
+
+	// Not stuck yet.
+	env.clock.Advance(30 * time.Second)
+	require.NoError(t, env.manager.Poll(ctx))
+	assert.Len(t, mtx.Hashes(), 1)
+
+	env.clock.Advance(time.Minute)
+	require.NoError(t, env.manager.Poll(ctx))
+	require.Len(t, mtx.Hashes(), 2)
+	bumped := env.backend.lastSent()
+	assert.Equal(t, big.NewInt(1100), bumped.MaxFeePerGas)
+	assert.Equal(t, big.NewInt(110), bumped.MaxPriorityFeePerGas)
+	assert.Equal(t, mtx.Hashes()[1], mtx.Hash())
+
+	// The second bump is capped.
+	env.clock.Advance(time.Minute)
+	require.NoError(t, env.manager.Poll(ctx))
+	assert.Equal(t, big.NewInt(1150), env.backend.lastSent().MaxFeePerGas)
+
+	// At the cap, no further bumps are sent.
+	env.clock.Advance(time.Minute)
+	require.NoError(t, env.manager.Poll(ctx))
+	assert.Len(t, mtx.Hashes(), 3)
+
+	// An older version may still win.
+	env.backend.include(mtx.Hashes()[0])
+	require.NoError(t, env.manager.Poll(ctx))
+	assert.Equal(t, StateIncluded, mtx.State())
+	assert.Equal(t, mtx.ID(), mtx.Hash())
+	assert.Equal(t, []State{StatePending, StatePending, StatePending, StateIncluded}, env.states())
+}
+
+func TestManager_BumpBroadcastFailure(t *testing.T) {
+	t.Run("timeout", func(t *testing.T) {
+		store := NewMemoryStore()
+		env := newTestEnv(t, func(cfg *Config) { cfg.Store = store })
+		ctx := context.Background()
+
+		mtx, err := env.manager.Send(ctx, newTx())
+		require.NoError(t, err)
+
+		// The bump times out, but the node may have accepted it.
+		env.backend.sendErr = errors.New("context deadline exceeded")
+		env.clock.Advance(2 * time.Minute)
+		require.ErrorIs(t, env.manager.Poll(ctx), ErrBroadcastUncertain)
+		require.Len(t, mtx.Hashes(), 2)
+
+		entries, err := store.Load()
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Len(t, entries[0].Versions, 2)
+
+		// The bump is mined anyway.
+		bumped := mtx.Hashes()[1]
+		env.backend.sendErr = nil
+		env.backend.receipts[bumped] = &client.Receipt{TxHash: bumped, Status: client.ReceiptStatusSuccessful}
+		env.backend.setNonce(0, 1)
+		require.NoError(t, env.manager.Poll(ctx))
+		assert.Equal(t, StateIncluded, mtx.State())
+		assert.Equal(t, bumped, mtx.Hash())
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		store := NewMemoryStore()
+		env := newTestEnv(t, func(cfg *Config) { cfg.Store = store })
+		ctx := context.Background()
+
+		mtx, err := env.manager.Send(ctx, newTx())
+		require.NoError(t, err)
+
+		env.backend.sendErr = &client.JSONRPCError{Code: -32000, Message: "replacement transaction underpriced"}
+		env.clock.Advance(2 * time.Minute)
+		err = env.manager.Poll(ctx)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrBroadcastUncertain)
+		assert.Len(t, mtx.Hashes(), 1)
+
+		entries, err := store.Load()
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Len(t, entries[0].Versions, 1)
+	})
+}
+
+func TestManager_SponsoredBump(t *testing.T) {
+	feePayer, err := signer.NewSigner(feePayerKey)
+	require.NoError(t, err)
+	env := newTestEnv(t, func(cfg *Config) { cfg.FeePayer = feePayer })
+	ctx := context.Background()
+
+	tx := newTx()
+	tx.AwaitingFeePayer = true
+	_, err = env.manager.Send(ctx, tx)
+	require.NoError(t, err)
+
+	env.clock.Advance(2 * time.Minute)
+	require.NoError(t, env.manager.Poll(ctx))
+
+	require.Len(t, env.backend.sent, 2)
+	for _, sent := range env.backend.sent {
+		sender, payer, err := transaction.VerifyDualSignatures(sent)
+		require.NoError(t, err)
+		assert.Equal(t, env.manager.cfg.Signer.Address(), sender)
+		assert.Equal(t, feePayer.Address(), payer)
+	}
+	assert.NotEqual(t, env.backend.sent[0].FeePayerSignature, env.backend.sent[1].FeePayerSignature)
+}
+
+func TestManager_SponsoredWithoutFeePayer(t *testing.T) {
+	env := newTestEnv(t, nil)
+	tx := newTx()
+	tx.AwaitingFeePayer = true
+
+	_, err := env.manager.Send(context.Background(), tx)
+	assert.Error(t, err)
+	assert.Empty(t, env.backend.sent)
+}
+
+func TestManager_Cancel(t *testing.T) {
+	env := newTestEnv(t, nil)
+	ctx := context.Background()
+
+	tx := newTx()
+	tx.NonceKey = big.NewInt(3)
+	tx.Nonce = 0
+	mtx, err := env.manager.Send(ctx, tx)
+	require.NoError(t, err)
+
+	require.NoError(t, env.manager.Cancel(ctx, mtx))
+	assert.Equal(t, StateCancelling, mtx.State())
+
+	cancel := env.backend.lastSent()
+	assert.Equal(t, big.NewInt(3), cancel.NonceKey)
+	assert.Equal(t, uint64(0), cancel.Nonce)
+	require.Len(t, cancel.Calls, 1)
+	assert.Equal(t, env.manager.cfg.Signer.Address(), *cancel.Calls[0].To)
+	assert.Empty(t, cancel.Calls[0].Data)
+	assert.Equal(t, big.NewInt(1100), cancel.MaxFeePerGas)
+
+	env.backend.include(mtx.Hash())
+	require.NoError(t, env.manager.Poll(ctx))
+	assert.Equal(t, StateCancelled, mtx.State())
+	assert.Equal(t, []State{StatePending, StateCancelling, StateCancelled}, env.states())
+
+	assert.Error(t, env.manager.Cancel(ctx, mtx))
+}
+
+func TestManager_Dropped(t *testing.T) {
+	env := newTestEnv(t, nil)
+	ctx := context.Background()
+
+	mtx, err := env.manager.Send(ctx, newTx())
+	require.NoError(t, err)
+
+	// Another transaction consumes nonce 0.
+	env.backend.setNonce(0, 1)
+	require.NoError(t, env.manager.Poll(ctx))
+
+	_, err = mtx.Wait(ctx)
+	assert.True(t, errors.Is(err, ErrDropped))
+	assert.Equal(t, []State{StatePending, StateDropped}, env.states())
+}
+
+func TestManager_Expired(t *testing.T) {
+	env := newTestEnv(t, nil)
+	ctx := context.Background()
+
+	tx := newTx()
+	tx.ValidBefore = uint64(env.clock.Now().Add(10 * time.Second).Unix())
+	mtx, err := env.manager.Send(ctx, tx)
+	require.NoError(t, err)
+
+	env.clock.Advance(20 * time.Second)
+	require.NoError(t, env.manager.Poll(ctx))
+
+	_, err = mtx.Wait(ctx)
+	assert.True(t, errors.Is(err, ErrExpired))
+}
+
+func TestManager_NonceManager(t *testing.T) {
+	nonceReader := &staticNonces{nonce: 4}
+	nonces := client.NewNonceManager(nonceReader, common.Address{}, big.NewInt(1))
+	env := newTestEnv(t, func(cfg *Config) { cfg.Nonces = nonces })
+	ctx := context.Background()
+
+	env.backend.sendErr = &client.JSONRPCError{Code: -32000, Message: "insufficient funds"}
+	_, err := env.manager.Send(ctx, newTx())
+	require.Error(t, err)
+
+	// The rejected nonce is reused.
+	env.backend.sendErr = nil
+	mtx, err := env.manager.Send(ctx, newTx())
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1), mtx.Tx().NonceKey)
+	assert.Equal(t, uint64(4), mtx.Tx().Nonce)
+}
+
+func TestManager_SendTransportFailure(t *testing.T) {
+	store := NewMemoryStore()
+	nonces := client.NewNonceManager(&staticNonces{nonce: 4}, common.Address{}, big.NewInt(1))
+	env := newTestEnv(t, func(cfg *Config) {
+		cfg.Store = store
+		cfg.Nonces = nonces
+	})
+	ctx := context.Background()
+
+	// The request timed out, so the node may have accepted the transaction.
+	env.backend.sendErr = errors.New("context deadline exceeded")
+	mtx, err := env.manager.Send(ctx, newTx())
+	require.ErrorIs(t, err, ErrBroadcastUncertain)
+	require.NotNil(t, mtx)
+	assert.Equal(t, StatePending, mtx.State())
+	assert.Len(t, env.manager.Pending(), 1)
+
+	entries, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, StatePending, entries[0].State)
+
+	// The nonce is still held.
+	env.backend.sendErr = nil
+	next, err := env.manager.Send(ctx, newTx())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), next.Tx().Nonce)
+
+	// After a restart, Replay broadcasts the uncertain transaction again.
+	restarted := newTestEnv(t, func(cfg *Config) { cfg.Store = store })
+	replayed, err := restarted.manager.Replay(ctx)
+	require.NoError(t, err)
+	require.Len(t, replayed, 2)
+	assert.Equal(t, mtx.ID(), replayed[0].ID())
+	require.Len(t, restarted.backend.sent, 2)
+	hash, err := restarted.backend.sent[0].Hash()
+	require.NoError(t, err)
+	assert.Equal(t, mtx.Hash(), hash)
+}
+
+func TestRejected(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"node rejection", &client.JSONRPCError{Code: -32000, Message: "nonce too low"}, true},
+		{"chain ID mismatch", fmt.Errorf("%w: transaction has 1, node has 2", client.ErrChainIDMismatch), true},
+		{"invalid transaction", fmt.Errorf("%w: gas must be greater than 0", transaction.ErrInvalidTransaction), true},
+		{"rate limited", &client.JSONRPCError{Code: -32005, Message: "rate limit exceeded"}, false},
+		{"HTTP 503", &client.HTTPError{StatusCode: 503}, false},
+		{"transport", errors.New("connection reset by peer"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rejected(tt.err))
+		})
+	}
+}
+
+func TestBumpValue(t *testing.T) {
+	assert.Equal(t, big.NewInt(110), bumpValue(big.NewInt(100), 10))
+	assert.Equal(t, big.NewInt(2), bumpValue(big.NewInt(1), 10))
+	assert.Equal(t, big.NewInt(1), bumpValue(big.NewInt(0), 10))
+}
+
+// staticNonces reports the same chain nonce for every key.
+type staticNonces struct {
+	nonce uint64
+}
+
+func (s *staticNonces) GetNonces(_ context.Context, _ common.Address, keys []*big.Int, _ client.BlockSelector) ([]uint64, error) {
+	nonces := make([]uint64, len(keys))
+	for i := range nonces {
+		nonces[i] = s.nonce
+	}
+	return nonces, nil
+}
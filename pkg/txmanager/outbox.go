@@ -0,0 +1,285 @@
+package txmanager
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Entry is the durable record of a managed transaction.
+type Entry struct {
+	// ID is the hash of the first broadcast version.
+	ID common.Hash `json:"id"`
+
+	// RawTx is the latest signed version, serialized.
+	RawTx string `json:"rawTx"`
+
+	// Versions lists every signed version, oldest first.
+	Versions []EntryVersion `json:"versions"`
+
+	// Sponsored is true if the transaction is co-signed by a fee payer.
+	Sponsored bool `json:"sponsored,omitempty"`
+
+	// State is the last known state.
+	State State `json:"state"`
+
+	// UpdatedAt is when the entry was last saved.
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// EntryVersion is one signed version of a managed transaction.
+type EntryVersion struct {
+	Hash   common.Hash `json:"hash"`
+	Cancel bool        `json:"cancel,omitempty"`
+}
+
+// Store persists outbox entries so that signed transactions survive restarts.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Save inserts or replaces the entry with the same ID.
+	Save(entry Entry) error
+
+	// Load returns the latest version of every entry, oldest first.
+	Load() ([]Entry, error)
+}
+
+// MarshalText encodes the state as its name.
+func (s State) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a state from its name.
+func (s *State) UnmarshalText(text []byte) error {
+	for state := StatePending; state <= StateRejected; state++ {
+		if state.String() == string(text) {
+			*s = state
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown transaction state %q", text)
+}
+
+// MemoryStore is an in-memory Store, useful for tests and for processes that do not
+// need to survive restarts.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[common.Hash]Entry
+	order   []common.Hash
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[common.Hash]Entry)}
+}
+
+// Save inserts or replaces an entry.
+func (s *MemoryStore) Save(entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.entries[entry.ID]; !ok {
+		s.order = append(s.order, entry.ID)
+	}
+	s.entries[entry.ID] = cloneEntry(entry)
+	return nil
+}
+
+// Load returns all entries in insertion order.
+func (s *MemoryStore) Load() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := make([]Entry, 0, len(s.order))
+	for _, id := range s.order {
+		entries = append(entries, cloneEntry(s.entries[id]))
+	}
+	return entries, nil
+}
+
+// FileStore is a Store backed by a JSON-lines file. Every Save appends one line and
+// syncs it to disk; Load replays the file, keeping the last line of each entry.
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// NewFileStore opens or creates the JSON-lines file at path. A truncated last line,
+// as left by a crash during Save, is removed so that new entries start on a line of
+// their own.
+func NewFileStore(path string) (*FileStore, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outbox: %w", err)
+	}
+	if err := truncatePartialLine(file); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to repair outbox: %w", err)
+	}
+	return &FileStore{path: path, file: file}, nil
+}
+
+// truncatePartialLine cuts the file back to just after its last newline.
+func truncatePartialLine(file *os.File) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	size := info.Size()
+
+	var cut int64
+	buf := make([]byte, 4096)
+	for end := size; end > 0; {
+		start := end - int64(len(buf))
+		if start < 0 {
+			start = 0
+		}
+		chunk := buf[:end-start]
+		if _, err := file.ReadAt(chunk, start); err != nil {
+			return err
+		}
+		if i := bytes.LastIndexByte(chunk, '\n'); i >= 0 {
+			cut = start + int64(i) + 1
+			break
+		}
+		end = start
+	}
+	if cut == size {
+		return nil
+	}
+	if err := file.Truncate(cut); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// Save appends an entry to the file.
+func (s *FileStore) Save(entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(line); err != nil {
+		return fmt.Errorf("failed to write outbox entry: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync outbox: %w", err)
+	}
+	return nil
+}
+
+// Load reads all entries from the file. A truncated last line, as left by a crash
+// during Save, is ignored.
+func (s *FileStore) Load() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+func (s *FileStore) load() ([]Entry, error) {
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outbox: %w", err)
+	}
+	defer file.Close()
+
+	entries := make(map[common.Hash]Entry)
+	first := make(map[common.Hash]int)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			if isLastLine(scanner) {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode outbox line %d: %w", line, err)
+		}
+		if _, ok := first[entry.ID]; !ok {
+			first[entry.ID] = line
+		}
+		entries[entry.ID] = entry
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read outbox: %w", err)
+	}
+
+	result := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, entry)
+	}
+	sort.Slice(result, func(i, j int) bool { return first[result[i].ID] < first[result[j].ID] })
+	return result, nil
+}
+
+// isLastLine advances the scanner and reports whether the current line was the last.
+func isLastLine(scanner *bufio.Scanner) bool {
+	return !scanner.Scan()
+}
+
+// Compact rewrites the file with only the latest line of every entry that is not in
+// a final state.
+func (s *FileStore) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	tmpPath := s.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create compacted outbox: %w", err)
+	}
+	encoder := json.NewEncoder(tmp)
+	for _, entry := range entries {
+		if entry.State.Final() {
+			continue
+		}
+		if err := encoder.Encode(entry); err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to write compacted outbox: %w", err)
+		}
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync compacted outbox: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to replace outbox: %w", err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to reopen outbox: %w", err)
+	}
+	s.file.Close()
+	s.file = file
+	return nil
+}
+
+// Close closes the underlying file.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+func cloneEntry(entry Entry) Entry {
+	entry.Versions = append([]EntryVersion(nil), entry.Versions...)
+	return entry
+}
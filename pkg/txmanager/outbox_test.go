@@ -0,0 +1,232 @@
+package txmanager
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempoxyz/tempo-go/pkg/client"
+)
+
+func testEntry(id string, state State) Entry {
+	hash := common.HexToHash(id)
+	return Entry{
+		ID:        hash,
+		RawTx:     "0x76c0",
+		Versions:  []EntryVersion{{Hash: hash}},
+		State:     state,
+		UpdatedAt: time.Unix(1700000000, 0).UTC(),
+	}
+}
+
+func TestStores(t *testing.T) {
+	newFileStore := func(t *testing.T) Store {
+		store, err := NewFileStore(filepath.Join(t.TempDir(), "outbox.jsonl"))
+		require.NoError(t, err)
+		t.Cleanup(func() { store.Close() })
+		return store
+	}
+
+	stores := map[string]func(t *testing.T) Store{
+		"memory": func(*testing.T) Store { return NewMemoryStore() },
+		"file":   newFileStore,
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore(t)
+
+			require.NoError(t, store.Save(testEntry("0x01", StatePending)))
+			require.NoError(t, store.Save(testEntry("0x02", StatePending)))
+
+			updated := testEntry("0x01", StateCancelling)
+			updated.Versions = append(updated.Versions, EntryVersion{Hash: common.HexToHash("0x03"), Cancel: true})
+			require.NoError(t, store.Save(updated))
+
+			entries, err := store.Load()
+			require.NoError(t, err)
+			require.Len(t, entries, 2)
+			assert.Equal(t, updated, entries[0])
+			assert.Equal(t, testEntry("0x02", StatePending), entries[1])
+		})
+	}
+}
+
+func TestFileStore_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	store, err := NewFileStore(path)
+	require.NoError(t, err)
+	require.NoError(t, store.Save(testEntry("0x01", StatePending)))
+	require.NoError(t, store.Save(testEntry("0x02", StateIncluded)))
+	require.NoError(t, store.Close())
+
+	// Simulate a crash in the middle of a write.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"id":"0x00000000`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	store, err = NewFileStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+
+	entries, err := store.Load()
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	require.NoError(t, store.Compact())
+	entries, err = store.Load()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, common.HexToHash("0x01"), entries[0].ID)
+
+	// Saves after compaction are appended to the new file.
+	require.NoError(t, store.Save(testEntry("0x04", StatePending)))
+	entries, err = store.Load()
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestFileStore_SaveAfterCrash(t *testing.T) {
+	tests := []struct {
+		name    string
+		saved   bool // whether a complete line precedes the partial one
+		partial string
+	}{
+		{"after complete lines", true, `{"id":"0x00000000`},
+		{"only line", false, `{"id":"0x00000000`},
+		{"longer than a read chunk", true, `{"id":"` + strings.Repeat("0", 10000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "outbox.jsonl")
+			store, err := NewFileStore(path)
+			require.NoError(t, err)
+			if tt.saved {
+				require.NoError(t, store.Save(testEntry("0x01", StatePending)))
+			}
+			require.NoError(t, store.Close())
+
+			// Simulate a crash in the middle of a write.
+			f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+			require.NoError(t, err)
+			_, err = f.WriteString(tt.partial)
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+
+			store, err = NewFileStore(path)
+			require.NoError(t, err)
+			defer store.Close()
+			require.NoError(t, store.Save(testEntry("0x02", StatePending)))
+			require.NoError(t, store.Save(testEntry("0x03", StatePending)))
+
+			entries, err := store.Load()
+			require.NoError(t, err)
+			ids := make([]common.Hash, len(entries))
+			for i, entry := range entries {
+				ids[i] = entry.ID
+			}
+			want := []common.Hash{common.HexToHash("0x02"), common.HexToHash("0x03")}
+			if tt.saved {
+				want = append([]common.Hash{common.HexToHash("0x01")}, want...)
+			}
+			assert.Equal(t, want, ids)
+		})
+	}
+}
+
+func TestFileStore_CorruptLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("not json\n{}\n"), 0o600))
+
+	store, err := NewFileStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+
+	_, err = store.Load()
+	assert.Error(t, err)
+}
+
+func TestManager_Replay(t *testing.T) {
+	store := NewMemoryStore()
+	env := newTestEnv(t, func(cfg *Config) { cfg.Store = store })
+	ctx := context.Background()
+
+	valid, err := env.manager.Send(ctx, newTx())
+	require.NoError(t, err)
+
+	expiring := newTx()
+	expiring.NonceKey.SetInt64(1)
+	expiring.ValidBefore = uint64(env.clock.Now().Add(time.Minute).Unix())
+	_, err = env.manager.Send(ctx, expiring)
+	require.NoError(t, err)
+
+	rejected := newTx()
+	rejected.NonceKey.SetInt64(2)
+	env.backend.sendErr = &client.JSONRPCError{Code: -32000, Message: "insufficient funds for gas"}
+	_, err = env.manager.Send(ctx, rejected)
+	require.Error(t, err)
+
+	entries, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, StatePending, entries[0].State)
+	assert.Equal(t, StateRejected, entries[2].State)
+
+	// Restart: a new manager replays the outbox after the first transaction expired.
+	env.clock.Advance(2 * time.Minute)
+	env.backend.sendErr = &client.JSONRPCError{Code: -32000, Message: "already known"}
+	restarted := newTestEnv(t, func(cfg *Config) {
+		cfg.Store = store
+		cfg.Now = env.clock.Now
+	})
+	// The restarted process talks to the same chain.
+	restarted.backend = env.backend
+	restarted.manager.backend = env.backend
+	sentBefore := len(env.backend.sent)
+
+	replayed, err := restarted.manager.Replay(ctx)
+	require.NoError(t, err, "already known counts as success")
+	require.Len(t, replayed, 2)
+	assert.Equal(t, valid.ID(), replayed[0].ID())
+	assert.Equal(t, valid.Hash(), replayed[0].Hash())
+	assert.Len(t, env.backend.sent, sentBefore, "nothing new was accepted")
+
+	env.backend.sendErr = nil
+	env.backend.include(valid.ID())
+	require.NoError(t, restarted.manager.Poll(ctx))
+	assert.Equal(t, StateIncluded, replayed[0].State())
+	assert.Equal(t, StateExpired, replayed[1].State())
+
+	entries, err = store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, StateIncluded, entries[0].State)
+	assert.Equal(t, StateExpired, entries[1].State)
+}
+
+func TestManager_ReplayBroadcastsValid(t *testing.T) {
+	store := NewMemoryStore()
+	env := newTestEnv(t, func(cfg *Config) { cfg.Store = store })
+	ctx := context.Background()
+
+	mtx, err := env.manager.Send(ctx, newTx())
+	require.NoError(t, err)
+
+	restarted := newTestEnv(t, func(cfg *Config) { cfg.Store = store })
+	replayed, err := restarted.manager.Replay(ctx)
+	require.NoError(t, err)
+	require.Len(t, replayed, 1)
+
+	require.Len(t, restarted.backend.sent, 1)
+	hash, err := restarted.backend.sent[0].Hash()
+	require.NoError(t, err)
+	assert.Equal(t, mtx.Hash(), hash)
+}
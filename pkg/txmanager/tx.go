@@ -0,0 +1,261 @@
+package txmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tempoxyz/tempo-go/pkg/client"
+	"github.com/tempoxyz/tempo-go/pkg/transaction"
+)
+
+// Sentinel errors returned by ManagedTx.Wait.
+var (
+	// ErrDropped is returned when the transaction's nonce was consumed by another transaction.
+	ErrDropped = errors.New("transaction dropped")
+
+	// ErrExpired is returned when the transaction's ValidBefore passed before inclusion.
+	ErrExpired = errors.New("transaction expired")
+)
+
+// ErrBroadcastUncertain is returned by Send, Cancel and Poll when broadcasting failed
+// without the node rejecting the transaction, for example on a timeout, so it may
+// have reached the node. The transaction, or its replacement, is still tracked.
+var ErrBroadcastUncertain = errors.New("broadcast outcome unknown")
+
+// State is the lifecycle state of a managed transaction.
+type State int
+
+const (
+	// StatePending means the transaction was broadcast and is awaiting inclusion.
+	StatePending State = iota
+
+	// StateCancelling means a cancellation was broadcast and is awaiting inclusion.
+	StateCancelling
+
+	// StateIncluded means one version of the transaction was included. The receipt
+	// may still report a revert.
+	StateIncluded
+
+	// StateCancelled means the cancellation was included instead of the transaction.
+	StateCancelled
+
+	// StateDropped means another transaction consumed the nonce.
+	StateDropped
+
+	// StateExpired means ValidBefore passed before the transaction was included.
+	StateExpired
+
+	// StateRejected means the node refused the transaction when it was first sent.
+	// It is only recorded in the outbox; Send returns the error instead.
+	StateRejected
+)
+
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case StatePending:
+		return "pending"
+	case StateCancelling:
+		return "cancelling"
+	case StateIncluded:
+		return "included"
+	case StateCancelled:
+		return "cancelled"
+	case StateDropped:
+		return "dropped"
+	case StateExpired:
+		return "expired"
+	case StateRejected:
+		return "rejected"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// Final returns true if the manager no longer tracks transactions in this state.
+func (s State) Final() bool {
+	return s >= StateIncluded
+}
+
+// Event reports a state change or resubmission of a managed transaction.
+type Event struct {
+	Tx    *ManagedTx
+	State State
+
+	// Hash is the hash of the latest broadcast version, or of the included version
+	// once the transaction is included or cancelled.
+	Hash common.Hash
+
+	// Receipt is set for StateIncluded and StateCancelled.
+	Receipt *client.Receipt
+}
+
+// version is one broadcast version of a managed transaction.
+type version struct {
+	hash   common.Hash
+	cancel bool
+}
+
+// ManagedTx is a transaction tracked by a Manager. It is safe for concurrent use.
+type ManagedTx struct {
+	id   common.Hash
+	done chan struct{}
+
+	mu           sync.Mutex
+	tx           *transaction.Tx
+	unsigned     *transaction.Tx
+	sponsored    bool
+	versions     []version
+	lastSent     time.Time
+	bumps        int
+	state        State
+	receipt      *client.Receipt
+	includedHash common.Hash
+}
+
+func newManagedTx(signed, unsigned *transaction.Tx, sponsored bool, now time.Time) (*ManagedTx, error) {
+	hash, err := signed.Hash()
+	if err != nil {
+		return nil, err
+	}
+	return &ManagedTx{
+		id:        hash,
+		done:      make(chan struct{}),
+		tx:        signed,
+		unsigned:  unsigned,
+		sponsored: sponsored,
+		versions:  []version{{hash: hash}},
+		lastSent:  now,
+		state:     StatePending,
+	}, nil
+}
+
+// managedTxFromEntry rebuilds a managed transaction from its outbox record.
+func managedTxFromEntry(entry Entry, now time.Time) (*ManagedTx, error) {
+	signed, err := transaction.Deserialize(entry.RawTx)
+	if err != nil {
+		return nil, err
+	}
+	if len(entry.Versions) == 0 {
+		return nil, errors.New("outbox entry has no versions")
+	}
+
+	unsigned := signed.Clone()
+	mtx := &ManagedTx{
+		id:        entry.ID,
+		done:      make(chan struct{}),
+		tx:        signed,
+		unsigned:  unsigned,
+		sponsored: entry.Sponsored,
+		versions:  make([]version, len(entry.Versions)),
+		lastSent:  now,
+		bumps:     len(entry.Versions) - 1,
+		state:     entry.State,
+	}
+	for i, v := range entry.Versions {
+		mtx.versions[i] = version{hash: v.Hash, cancel: v.Cancel}
+	}
+	return mtx, nil
+}
+
+// ID returns the hash of the first broadcast version, which identifies the
+// transaction across bumps.
+func (t *ManagedTx) ID() common.Hash {
+	return t.id
+}
+
+// Hash returns the hash of the included version once included or cancelled, and of
+// the latest broadcast version otherwise.
+func (t *ManagedTx) Hash() common.Hash {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.includedHash != (common.Hash{}) {
+		return t.includedHash
+	}
+	return t.versions[len(t.versions)-1].hash
+}
+
+// Hashes returns the hashes of all broadcast versions, oldest first.
+func (t *ManagedTx) Hashes() []common.Hash {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	hashes := make([]common.Hash, len(t.versions))
+	for i, v := range t.versions {
+		hashes[i] = v.hash
+	}
+	return hashes
+}
+
+// Tx returns the latest signed version of the transaction.
+func (t *ManagedTx) Tx() *transaction.Tx {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.tx
+}
+
+// State returns the current state.
+func (t *ManagedTx) State() State {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.state
+}
+
+// Receipt returns the receipt of the included version, or nil.
+func (t *ManagedTx) Receipt() *client.Receipt {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.receipt
+}
+
+// Done returns a channel that is closed when the transaction reaches a final state.
+func (t *ManagedTx) Done() <-chan struct{} {
+	return t.done
+}
+
+// Wait blocks until the transaction reaches a final state and returns its receipt.
+// Returns ErrDropped or ErrExpired if the transaction was not included.
+func (t *ManagedTx) Wait(ctx context.Context) (*client.Receipt, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-t.done:
+	}
+
+	switch state := t.State(); state {
+	case StateDropped:
+		return nil, ErrDropped
+	case StateExpired:
+		return nil, ErrExpired
+	default:
+		return t.Receipt(), nil
+	}
+}
+
+// txSnapshot is a consistent copy of the fields poll needs.
+type txSnapshot struct {
+	unsigned   *transaction.Tx
+	sponsored  bool
+	versions   []version
+	lastSent   time.Time
+	bumps      int
+	state      State
+	cancelling bool
+}
+
+func (t *ManagedTx) snapshot() txSnapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return txSnapshot{
+		unsigned:   t.unsigned,
+		sponsored:  t.sponsored,
+		versions:   append([]version(nil), t.versions...),
+		lastSent:   t.lastSent,
+		bumps:      t.bumps,
+		state:      t.state,
+		cancelling: t.state == StateCancelling,
+	}
+}